@@ -0,0 +1,21 @@
+package assessment
+
+import (
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"gorm.io/gorm"
+)
+
+type AssessmentContainer struct {
+	Handler *Handler
+}
+
+func NewAssessmentContainer(db *gorm.DB, topicRepo studytopic.StudyTopicRepository) *AssessmentContainer {
+	repo := NewRepository(db)
+	service := NewService(repo, studysubject.NewRepository(db), topicRepo)
+	handler := NewHandler(service)
+
+	return &AssessmentContainer{
+		Handler: handler,
+	}
+}
@@ -0,0 +1,84 @@
+package assessment
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const (
+	defaultWeight    = 1
+	defaultMaxScore  = 10
+	maxReminderDays  = 60
+	maxReminderCount = 10
+)
+
+// lembretes padrão: uma semana, três dias e um dia antes da avaliação
+var defaultReminderDays = []int{7, 3, 1}
+
+var (
+	ErrInvalidAssessment = errors.New("title and date are required")
+	ErrInvalidKind       = errors.New("invalid assessment kind")
+	ErrInvalidScore      = errors.New("weight cannot be negative, max score must be positive and score must be between 0 and max score")
+	ErrInvalidReminders  = errors.New("reminder days must be between 0 and 60, at most 10 entries")
+	ErrInvalidTarget     = errors.New("target must be between 0 and 100")
+)
+
+type AssessmentDTO struct {
+	Title        string              `json:"title"`
+	Kind         AssessmentKind      `json:"kind"`
+	Date         *util.LocalDateTime `json:"date"`
+	Weight       *float64            `json:"weight"`
+	MaxScore     *float64            `json:"max_score"`
+	Score        *float64            `json:"score"`
+	TopicIDs     []string            `json:"topic_ids"`
+	ReminderDays []int               `json:"reminder_days"`
+}
+
+func (dto *AssessmentDTO) Validate() error {
+	if strings.TrimSpace(dto.Title) == "" || dto.Date == nil || dto.Date.IsZero() {
+		return ErrInvalidAssessment
+	}
+	if dto.Kind != "" && !dto.Kind.IsValid() {
+		return ErrInvalidKind
+	}
+	if dto.Weight != nil && *dto.Weight < 0 {
+		return ErrInvalidScore
+	}
+	maxScore := float64(defaultMaxScore)
+	if dto.MaxScore != nil {
+		maxScore = *dto.MaxScore
+	}
+	if maxScore <= 0 {
+		return ErrInvalidScore
+	}
+	if dto.Score != nil && (*dto.Score < 0 || *dto.Score > maxScore) {
+		return ErrInvalidScore
+	}
+	if len(dto.ReminderDays) > maxReminderCount {
+		return ErrInvalidReminders
+	}
+	for _, d := range dto.ReminderDays {
+		if d < 0 || d > maxReminderDays {
+			return ErrInvalidReminders
+		}
+	}
+	return nil
+}
+
+// normalizedReminderDays remove repetidos e ordena do mais distante para o mais próximo.
+func normalizedReminderDays(days []int) []int {
+	seen := make(map[int]bool, len(days))
+	out := make([]int, 0, len(days))
+	for _, d := range days {
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		out = append(out, d)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out
+}
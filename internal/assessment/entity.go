@@ -0,0 +1,48 @@
+package assessment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+type Assessment struct {
+	ID           uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Title        string              `json:"title"`
+	Kind         AssessmentKind      `json:"kind"`
+	Date         *util.LocalDateTime `gorm:"not null;index" json:"date"`
+	Weight       float64             `json:"weight"`
+	MaxScore     float64             `json:"max_score"`
+	Score        *float64            `json:"score"`
+	ReminderDays []int               `gorm:"serializer:json" json:"reminder_days"`
+	SubjectID    uuid.UUID           `gorm:"column:subject_id;not null;index" json:"subject_id"`
+	UserID       uuid.UUID           `gorm:"column:user_id;not null" json:"user_id"`
+	TopicIDs     []uuid.UUID         `gorm:"-" json:"topic_ids"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// AssessmentTopic liga a avaliação aos tópicos da matéria que ela cobre.
+type AssessmentTopic struct {
+	AssessmentID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TopicID      uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+}
+
+func (a *Assessment) IsGraded() bool {
+	return a.Score != nil
+}
+
+// GradeSummary resume as notas da matéria. Percentuais vão de 0 a 100.
+type GradeSummary struct {
+	SubjectID       uuid.UUID   `json:"subject_id"`
+	Assessments     int         `json:"assessments"`
+	Graded          int         `json:"graded"`
+	TotalWeight     float64     `json:"total_weight"`
+	GradedWeight    float64     `json:"graded_weight"`
+	WeightedAverage *float64    `json:"weighted_average"`
+	Accumulated     float64     `json:"accumulated"`
+	Target          *float64    `json:"target,omitempty"`
+	RequiredAverage *float64    `json:"required_average,omitempty"`
+	NextAssessment  *Assessment `json:"next_assessment"`
+}
@@ -0,0 +1,26 @@
+package assessment
+
+type AssessmentKind string
+
+const (
+	EXAM         AssessmentKind = "EXAM"
+	QUIZ         AssessmentKind = "QUIZ"
+	ASSIGNMENT   AssessmentKind = "ASSIGNMENT"
+	PRESENTATION AssessmentKind = "PRESENTATION"
+)
+
+var AllKinds = []AssessmentKind{
+	EXAM,
+	QUIZ,
+	ASSIGNMENT,
+	PRESENTATION,
+}
+
+func (k AssessmentKind) IsValid() bool {
+	for _, v := range AllKinds {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package assessment
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const countdownTaskPrefix = "Avaliação"
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// summarizeGrades calcula a média ponderada das avaliações corrigidas e, com uma meta,
+// a média necessária nas avaliações restantes para alcançá-la.
+func summarizeGrades(subjectID uuid.UUID, assessments []*Assessment, target *float64, now time.Time) *GradeSummary {
+	summary := &GradeSummary{SubjectID: subjectID, Assessments: len(assessments), Target: target}
+
+	var earned float64
+	for _, a := range assessments {
+		summary.TotalWeight += a.Weight
+		if a.IsGraded() {
+			summary.Graded++
+			summary.GradedWeight += a.Weight
+			earned += a.Weight * *a.Score / a.MaxScore
+			continue
+		}
+		if a.Date.After(now) && (summary.NextAssessment == nil || a.Date.Before(summary.NextAssessment.Date.Time)) {
+			summary.NextAssessment = a
+		}
+	}
+
+	if summary.GradedWeight > 0 {
+		avg := round2(earned / summary.GradedWeight * 100)
+		summary.WeightedAverage = &avg
+	}
+	if summary.TotalWeight > 0 {
+		summary.Accumulated = round2(earned / summary.TotalWeight * 100)
+	}
+
+	pendingWeight := summary.TotalWeight - summary.GradedWeight
+	if target != nil && pendingWeight > 0 {
+		required := round2((*target/100*summary.TotalWeight - earned) / pendingWeight * 100)
+		if required < 0 {
+			required = 0
+		}
+		summary.RequiredAverage = &required
+	}
+	summary.TotalWeight = round2(summary.TotalWeight)
+	summary.GradedWeight = round2(summary.GradedWeight)
+	return summary
+}
+
+func countdownName(a *Assessment, days int) string {
+	switch days {
+	case 0:
+		return fmt.Sprintf("%s hoje: %s", countdownTaskPrefix, a.Title)
+	case 1:
+		return fmt.Sprintf("%s amanhã: %s", countdownTaskPrefix, a.Title)
+	default:
+		return fmt.Sprintf("%s em %d dias: %s", countdownTaskPrefix, days, a.Title)
+	}
+}
+
+// countdownTasks monta uma task STUDY por lembrete, com prazo no fim do dia do lembrete.
+// Lembretes que já passaram não são criados. localNow vem de util.LocalNow(), no mesmo horário
+// local da data da avaliação; now fica só para os carimbos das tasks.
+func countdownTasks(a *Assessment, localNow, now time.Time) []*task.Task {
+	tasks := make([]*task.Task, 0, len(a.ReminderDays))
+	assessmentID := a.ID
+	for _, days := range a.ReminderDays {
+		day := a.Date.AddDate(0, 0, -days)
+		due := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, time.UTC)
+		if days == 0 && a.Date.Before(due) {
+			due = a.Date.Time
+		}
+		if due.Before(localNow) {
+			continue
+		}
+
+		priority := task.MEDIUM
+		if days <= 1 {
+			priority = task.HIGH
+		}
+		tasks = append(tasks, &task.Task{
+			ID:           uuid.New(),
+			Name:         countdownName(a, days),
+			Status:       task.TODO,
+			Type:         task.STUDY,
+			Priority:     priority,
+			DueDate:      &util.LocalDateTime{Time: due},
+			AssessmentId: &assessmentID,
+			UserID:       a.UserID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+	return tasks
+}
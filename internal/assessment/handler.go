@@ -0,0 +1,128 @@
+package assessment
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+type Handler struct {
+	service AssessmentService
+}
+
+func NewHandler(s AssessmentService) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) CreateAssessment(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload AssessmentDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	a, err := h.service.CreateAssessment(r.Context(), chi.URLParam(r, "studySubjectId"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error creating assessment")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, a)
+}
+
+func (h *Handler) ListAssessments(w http.ResponseWriter, r *http.Request) {
+	assessments, err := h.service.ListAssessments(r.Context(), chi.URLParam(r, "studySubjectId"))
+	if err != nil {
+		writeError(w, r, err, "Error listing assessments")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":       len(assessments),
+		"assessments": assessments,
+	})
+}
+
+func (h *Handler) GetAssessment(w http.ResponseWriter, r *http.Request) {
+	a, err := h.service.GetAssessment(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error fetching assessment")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, a)
+}
+
+func (h *Handler) UpdateAssessment(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload AssessmentDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	a, err := h.service.UpdateAssessment(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error updating assessment")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, a)
+}
+
+func (h *Handler) DeleteAssessment(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteAssessment(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err, "Error deleting assessment")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "assessment deleted successfully",
+	})
+}
+
+func (h *Handler) GetGradeSummary(w http.ResponseWriter, r *http.Request) {
+	var target *float64
+	if raw := r.URL.Query().Get("target"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			http.Error(w, ErrInvalidTarget.Error(), http.StatusBadRequest)
+			return
+		}
+		target = &v
+	}
+
+	summary, err := h.service.GetGradeSummary(r.Context(), chi.URLParam(r, "studySubjectId"), target)
+	if err != nil {
+		writeError(w, r, err, "Error computing grade summary")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, summary)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrAssessmentNotFound):
+		http.Error(w, "assessment not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidAssessment), errors.Is(err, ErrInvalidKind), errors.Is(err, ErrInvalidScore),
+		errors.Is(err, ErrInvalidReminders), errors.Is(err, ErrInvalidTarget), errors.Is(err, ErrInvalidTopic):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,145 @@
+package assessment
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssessmentRepository interface {
+	Create(a *Assessment, reminders []*task.Task) error
+	GetByID(id string) (*Assessment, error)
+	ListBySubject(subjectID uuid.UUID) ([]*Assessment, error)
+	Update(a *Assessment, reminders []*task.Task, replaceReminders bool) error
+	Delete(id uuid.UUID) error
+}
+
+type assessmentRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) AssessmentRepository {
+	return &assessmentRepository{db: db}
+}
+
+func (r *assessmentRepository) Create(a *Assessment, reminders []*task.Task) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(a).Error; err != nil {
+			return err
+		}
+		if err := replaceTopics(tx, a); err != nil {
+			return err
+		}
+		return createReminders(tx, reminders)
+	})
+}
+
+func (r *assessmentRepository) GetByID(id string) (*Assessment, error) {
+	var a Assessment
+	if err := r.db.First(&a, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := loadTopicIDs(r.db, []*Assessment{&a}); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *assessmentRepository) ListBySubject(subjectID uuid.UUID) ([]*Assessment, error) {
+	var assessments []*Assessment
+	if err := r.db.Where("subject_id = ?", subjectID).Order("date, created_at").Find(&assessments).Error; err != nil {
+		return nil, err
+	}
+	if err := loadTopicIDs(r.db, assessments); err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+// Update grava a avaliação e, se pedido, troca os lembretes ainda não concluídos pelos novos.
+func (r *assessmentRepository) Update(a *Assessment, reminders []*task.Task, replaceReminders bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(a).Error; err != nil {
+			return err
+		}
+		if err := replaceTopics(tx, a); err != nil {
+			return err
+		}
+		if !replaceReminders {
+			return nil
+		}
+		if err := deleteOpenReminders(tx, a.ID); err != nil {
+			return err
+		}
+		return createReminders(tx, reminders)
+	})
+}
+
+// Delete remove a avaliação e seus lembretes em aberto; os concluídos ficam como tasks avulsas.
+func (r *assessmentRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteOpenReminders(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE tasks SET assessment_id = NULL WHERE assessment_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("assessment_id = ?", id).Delete(&AssessmentTopic{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Assessment{}, "id = ?", id).Error
+	})
+}
+
+func replaceTopics(tx *gorm.DB, a *Assessment) error {
+	if err := tx.Where("assessment_id = ?", a.ID).Delete(&AssessmentTopic{}).Error; err != nil {
+		return err
+	}
+	if len(a.TopicIDs) == 0 {
+		return nil
+	}
+	links := make([]AssessmentTopic, 0, len(a.TopicIDs))
+	for _, id := range a.TopicIDs {
+		links = append(links, AssessmentTopic{AssessmentID: a.ID, TopicID: id})
+	}
+	return tx.Create(&links).Error
+}
+
+func loadTopicIDs(db *gorm.DB, assessments []*Assessment) error {
+	if len(assessments) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*Assessment, len(assessments))
+	ids := make([]uuid.UUID, 0, len(assessments))
+	for _, a := range assessments {
+		a.TopicIDs = []uuid.UUID{}
+		byID[a.ID] = a
+		ids = append(ids, a.ID)
+	}
+
+	var links []AssessmentTopic
+	if err := db.Where("assessment_id IN ?", ids).Find(&links).Error; err != nil {
+		return err
+	}
+	for _, l := range links {
+		byID[l.AssessmentID].TopicIDs = append(byID[l.AssessmentID].TopicIDs, l.TopicID)
+	}
+	return nil
+}
+
+func deleteOpenReminders(tx *gorm.DB, assessmentID uuid.UUID) error {
+	return tx.Exec("DELETE FROM tasks WHERE assessment_id = ? AND status <> ?", assessmentID, task.DONE).Error
+}
+
+func createReminders(tx *gorm.DB, reminders []*task.Task) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Create(&reminders).Error
+}
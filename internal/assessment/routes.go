@@ -0,0 +1,32 @@
+package assessment
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+)
+
+func Routes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Get("/{id}", h.GetAssessment)
+	r.Put("/{id}", h.UpdateAssessment)
+	r.Delete("/{id}", h.DeleteAssessment)
+
+	return r
+}
+
+// SubjectRoutes expõe as avaliações de uma matéria, montado em /study-subjects/{studySubjectId}/assessments.
+func SubjectRoutes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Post("/", h.CreateAssessment)
+	r.Get("/", h.ListAssessments)
+
+	return r
+}
@@ -0,0 +1,296 @@
+package assessment
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrAssessmentNotFound   = errors.New("assessment not found")
+	ErrStudySubjectNotFound = studysubject.ErrStudySubjectNotFound
+	ErrInvalidTopic         = errors.New("covered topics must belong to the assessment's subject")
+	ErrUnauthorized         = errors.New("unauthorized")
+)
+
+type AssessmentService interface {
+	CreateAssessment(ctx context.Context, subjectID string, dto *AssessmentDTO) (*Assessment, error)
+	GetAssessment(ctx context.Context, id string) (*Assessment, error)
+	ListAssessments(ctx context.Context, subjectID string) ([]*Assessment, error)
+	UpdateAssessment(ctx context.Context, id string, dto *AssessmentDTO) (*Assessment, error)
+	DeleteAssessment(ctx context.Context, id string) error
+	GetGradeSummary(ctx context.Context, subjectID string, target *float64) (*GradeSummary, error)
+}
+
+type assessmentService struct {
+	repo        AssessmentRepository
+	subjectRepo studysubject.StudySubjectRepository
+	topicRepo   studytopic.StudyTopicRepository
+}
+
+func NewService(repo AssessmentRepository, subjectRepo studysubject.StudySubjectRepository, topicRepo studytopic.StudyTopicRepository) AssessmentService {
+	return &assessmentService{repo: repo, subjectRepo: subjectRepo, topicRepo: topicRepo}
+}
+
+func currentUserID(ctx context.Context, log logrus.FieldLogger, action string) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("Attempt to %s without authentication", action)
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+func (s *assessmentService) getOwnedSubject(ctx context.Context, subjectID, action string) (*studysubject.StudySubject, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(subjectID); err != nil {
+		return nil, ErrStudySubjectNotFound
+	}
+
+	subject, err := s.subjectRepo.GetByID(subjectID)
+	if err != nil {
+		log.WithError(err).Error("Error fetching study subject for assessments")
+		return nil, err
+	}
+	if subject == nil {
+		return nil, ErrStudySubjectNotFound
+	}
+	if !auth.IsOwner(userID.String(), subject.UserID) {
+		log.WithFields(logrus.Fields{
+			"subject_id": subject.ID,
+			"user_id":    userID,
+		}).Warnf("User attempted to %s on another user's subject", action)
+		return nil, ErrUnauthorized
+	}
+	return subject, nil
+}
+
+func (s *assessmentService) getOwnedAssessment(ctx context.Context, id, action string) (*Assessment, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrAssessmentNotFound
+	}
+
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		log.WithError(err).Error("Error fetching assessment by ID")
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAssessmentNotFound
+	}
+	if !auth.IsOwner(userID.String(), a.UserID) {
+		log.WithFields(logrus.Fields{
+			"assessment_id": a.ID,
+			"user_id":       userID,
+		}).Warnf("User attempted to %s another user's assessment", action)
+		return nil, ErrUnauthorized
+	}
+	return a, nil
+}
+
+// coveredTopics valida que os tópicos informados são da matéria, sem repetir.
+func (s *assessmentService) coveredTopics(subjectID uuid.UUID, raw []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	if len(raw) == 0 {
+		return ids, nil
+	}
+
+	topics, err := s.topicRepo.ListBySubject(subjectID.String())
+	if err != nil {
+		return nil, err
+	}
+	inSubject := make(map[uuid.UUID]bool, len(topics))
+	for _, t := range topics {
+		inSubject[t.ID] = true
+	}
+
+	for _, r := range raw {
+		id, err := uuid.Parse(r)
+		if err != nil || !inSubject[id] {
+			return nil, ErrInvalidTopic
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// apply copia o DTO para a avaliação, mantendo os lembretes atuais quando não informados.
+func apply(a *Assessment, dto *AssessmentDTO, topicIDs []uuid.UUID) {
+	a.Title = strings.TrimSpace(dto.Title)
+	a.Kind = dto.Kind
+	if a.Kind == "" {
+		a.Kind = EXAM
+	}
+	a.Date = dto.Date
+	a.Weight = defaultWeight
+	if dto.Weight != nil {
+		a.Weight = *dto.Weight
+	}
+	a.MaxScore = defaultMaxScore
+	if dto.MaxScore != nil {
+		a.MaxScore = *dto.MaxScore
+	}
+	a.Score = dto.Score
+	if dto.ReminderDays != nil {
+		a.ReminderDays = normalizedReminderDays(dto.ReminderDays)
+	} else if a.ReminderDays == nil {
+		a.ReminderDays = defaultReminderDays
+	}
+	a.TopicIDs = topicIDs
+}
+
+func (s *assessmentService) CreateAssessment(ctx context.Context, subjectID string, dto *AssessmentDTO) (*Assessment, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	subject, err := s.getOwnedSubject(ctx, subjectID, "create assessment")
+	if err != nil {
+		return nil, err
+	}
+	topicIDs, err := s.coveredTopics(subject.ID, dto.TopicIDs)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidTopic) {
+			log.WithError(err).Error("Error validating assessment topics")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	a := &Assessment{
+		ID:        uuid.New(),
+		SubjectID: subject.ID,
+		UserID:    subject.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	apply(a, dto, topicIDs)
+
+	reminders := countdownTasks(a, util.LocalNow(), now)
+	if err := s.repo.Create(a, reminders); err != nil {
+		log.WithError(err).Error("Failed to create assessment")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"assessment_id": a.ID,
+		"subject_id":    subject.ID,
+		"reminders":     len(reminders),
+	}).Info("Assessment created successfully")
+	return a, nil
+}
+
+func (s *assessmentService) GetAssessment(ctx context.Context, id string) (*Assessment, error) {
+	return s.getOwnedAssessment(ctx, id, "access")
+}
+
+func (s *assessmentService) ListAssessments(ctx context.Context, subjectID string) ([]*Assessment, error) {
+	log := config.WithContext(ctx)
+
+	subject, err := s.getOwnedSubject(ctx, subjectID, "list assessments")
+	if err != nil {
+		return nil, err
+	}
+
+	assessments, err := s.repo.ListBySubject(subject.ID)
+	if err != nil {
+		log.WithError(err).Error("Error listing assessments by subject")
+		return nil, err
+	}
+	return assessments, nil
+}
+
+func (s *assessmentService) UpdateAssessment(ctx context.Context, id string, dto *AssessmentDTO) (*Assessment, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	a, err := s.getOwnedAssessment(ctx, id, "update")
+	if err != nil {
+		return nil, err
+	}
+	topicIDs, err := s.coveredTopics(a.SubjectID, dto.TopicIDs)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidTopic) {
+			log.WithError(err).Error("Error validating assessment topics")
+		}
+		return nil, err
+	}
+
+	before := *a
+	apply(a, dto, topicIDs)
+	a.UpdatedAt = time.Now()
+
+	// os lembretes só são refeitos quando mudam a data, o título ou os dias de antecedência
+	replace := !a.Date.Equal(*before.Date) || a.Title != before.Title || !slices.Equal(a.ReminderDays, before.ReminderDays)
+	var reminders []*task.Task
+	if replace {
+		reminders = countdownTasks(a, util.LocalNow(), a.UpdatedAt)
+	}
+
+	if err := s.repo.Update(a, reminders, replace); err != nil {
+		log.WithError(err).Error("Failed to update assessment")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"assessment_id":      a.ID,
+		"reminders_replaced": replace,
+	}).Info("Assessment updated successfully")
+	return a, nil
+}
+
+func (s *assessmentService) DeleteAssessment(ctx context.Context, id string) error {
+	log := config.WithContext(ctx)
+
+	a, err := s.getOwnedAssessment(ctx, id, "delete")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(a.ID); err != nil {
+		log.WithError(err).Error("Failed to delete assessment")
+		return err
+	}
+
+	log.WithField("assessment_id", a.ID).Info("Assessment deleted successfully")
+	return nil
+}
+
+func (s *assessmentService) GetGradeSummary(ctx context.Context, subjectID string, target *float64) (*GradeSummary, error) {
+	if target != nil && (*target < 0 || *target > 100) {
+		return nil, ErrInvalidTarget
+	}
+
+	assessments, err := s.ListAssessments(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeGrades(uuid.MustParse(subjectID), assessments, target, util.LocalNow()), nil
+}
@@ -11,35 +11,65 @@ import (
 type UserDataKey string
 
 const (
-	UserDataKeyID   UserDataKey = "userID"
-	UserDataKeyRole UserDataKey = "userRole"
+	UserDataKeyID        UserDataKey = "userID"
+	UserDataKeyRole      UserDataKey = "userRole"
+	UserDataKeySessionID UserDataKey = "sessionID"
 )
 
+// SessionValidator diz se a sessão de um access token ainda está ativa. O JWT sozinho continua
+// válido até expirar; é essa consulta que faz logout e revogação valerem na hora.
+type SessionValidator interface {
+	ActiveSession(sessionID, userID string) (bool, error)
+}
+
+var sessionValidator SessionValidator
+
+// SetSessionValidator é chamado na montagem do container. Sem validador, só o JWT é conferido.
+func SetSessionValidator(v SessionValidator) {
+	sessionValidator = v
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tokenStr, err := extractToken(r)
+		tokenStr, err := ExtractToken(r)
 		if err != nil {
 			log.Printf("[AuthMiddleware] Token não encontrado: %v", err)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		claims, err := ValidateJWT(tokenStr)
+		claims, err := ValidateAccessToken(tokenStr)
 		if err != nil {
 			log.Printf("[AuthMiddleware] Falha ao validar JWT: %v", err)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
+		if sessionValidator != nil {
+			active, err := sessionValidator.ActiveSession(claims.SessionID, claims.UserID)
+			if err != nil {
+				log.Printf("[AuthMiddleware] Falha ao consultar sessão: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			if !active {
+				log.Printf("[AuthMiddleware] Sessão revogada ou expirada: %s", claims.SessionID)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, UserDataKeyID, claims.UserID)
 		ctx = context.WithValue(ctx, UserDataKeyRole, claims.Role)
+		ctx = context.WithValue(ctx, UserDataKeySessionID, claims.SessionID)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func extractToken(r *http.Request) (string, error) {
+// ExtractToken devolve o access token do cookie ou do header Authorization.
+func ExtractToken(r *http.Request) (string, error) {
 	cookie, err := r.Cookie("jwt")
 	if err == nil && cookie.Value != "" {
 		return cookie.Value, nil
@@ -6,8 +6,9 @@ import (
 )
 
 type ClaimsFromContext struct {
-	UserID string
-	Role   string
+	UserID    string
+	Role      string
+	SessionID string
 }
 
 var ErrNoAuthData = errors.New("no authentication data in context")
@@ -23,8 +24,11 @@ func GetUserClaimsFromContext(ctx context.Context) (*ClaimsFromContext, error) {
 		return nil, ErrNoAuthData
 	}
 
+	sessionID, _ := ctx.Value(UserDataKeySessionID).(string)
+
 	return &ClaimsFromContext{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 	}, nil
 }
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultGoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+var (
+	ErrIDTokenNotConfigured = errors.New("google id token verification is not configured")
+	ErrInvalidIDToken       = errors.New("invalid google id token")
+)
+
+// o Google emite ID tokens com e sem o esquema no iss
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+// GoogleIDClaims são as claims do ID token que usamos para identificar o usuário.
+type GoogleIDClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+type GoogleIDTokenVerifier struct {
+	audiences []string
+	keys      KeySource
+}
+
+func NewGoogleIDTokenVerifier(audiences []string, keys KeySource) (*GoogleIDTokenVerifier, error) {
+	if len(audiences) == 0 || keys == nil {
+		return nil, ErrIDTokenNotConfigured
+	}
+	return &GoogleIDTokenVerifier{audiences: audiences, keys: keys}, nil
+}
+
+// NewGoogleIDTokenVerifierFromEnv aceita como audiência o GOOGLE_CLIENT_ID e os IDs extras em
+// GOOGLE_ALLOWED_AUDIENCES (separados por vírgula). GOOGLE_JWKS_URL troca a origem das chaves.
+func NewGoogleIDTokenVerifierFromEnv() (*GoogleIDTokenVerifier, error) {
+	var audiences []string
+	for _, aud := range append([]string{os.Getenv("GOOGLE_CLIENT_ID")}, strings.Split(os.Getenv("GOOGLE_ALLOWED_AUDIENCES"), ",")...) {
+		if aud = strings.TrimSpace(aud); aud != "" {
+			audiences = append(audiences, aud)
+		}
+	}
+	url := os.Getenv("GOOGLE_JWKS_URL")
+	if url == "" {
+		url = defaultGoogleJWKSURL
+	}
+	return NewGoogleIDTokenVerifier(audiences, NewJWKSKeySource(url, nil))
+}
+
+// Verify confere assinatura RS256, emissor, audiência, validade e e-mail verificado.
+func (v *GoogleIDTokenVerifier) Verify(ctx context.Context, raw string) (*GoogleIDClaims, error) {
+	claims := &GoogleIDClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrUnknownKey
+		}
+		return v.keys.Key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		// mantém o erro do parser na cadeia para distinguir kid desconhecido de assinatura inválida
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if !slices.Contains(googleIssuers, claims.Issuer) {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, claims.Issuer)
+	}
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(v.audiences, aud) }) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, fmt.Errorf("%w: missing subject or email", ErrInvalidIDToken)
+	}
+	if !claims.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	return claims, nil
+}
@@ -1,14 +1,18 @@
 package auth
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var jwtSecret []byte
+var keyring *Keyring
 
 type contextKey string
 
@@ -17,44 +21,127 @@ const (
 	googleAccessTokenKey contextKey = "googleAccessToken"
 )
 
+// TokenType separa access de refresh token: cada um tem audiência própria e um não é aceito
+// no lugar do outro.
+type TokenType string
+
+const (
+	AccessTokenType  TokenType = "access"
+	RefreshTokenType TokenType = "refresh"
+
+	jwtIssuer       = "chronos"
+	accessAudience  = "chronos-api"
+	refreshAudience = "chronos-refresh"
+)
+
+var (
+	accessTokenTTL  = 24 * time.Hour
+	refreshTokenTTL = 14 * 24 * time.Hour
+	sessionMaxAge   = 90 * 24 * time.Hour
+)
+
 func Init() {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		panic("Error: JWT_SECRET environment variable is not set")
+	kr, err := LoadKeyringFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("Error: %v", err))
+	}
+	keyring = kr
+
+	accessTokenTTL = durationFromEnv("ACCESS_TOKEN_TTL", accessTokenTTL)
+	refreshTokenTTL = durationFromEnv("REFRESH_TOKEN_TTL", refreshTokenTTL)
+	sessionMaxAge = durationFromEnv("SESSION_MAX_AGE", sessionMaxAge)
+}
+
+// durationFromEnv lê durações no formato do time.ParseDuration, como "15m" ou "336h".
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		panic(fmt.Sprintf("Error: %s must be a positive duration, got %q", name, value))
 	}
-	jwtSecret = []byte(secret)
+	return d
 }
 
+func AccessTokenTTL() time.Duration  { return accessTokenTTL }
+func RefreshTokenTTL() time.Duration { return refreshTokenTTL }
+
+// SessionMaxAge limita a vida de uma sessão mesmo que o refresh token continue sendo girado.
+func SessionMaxAge() time.Duration { return sessionMaxAge }
+
 // Claims representa as informações do usuário no JWT
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	Type      TokenType `json:"typ"`
+	SessionID string    `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID, role string, duration time.Duration) (string, error) {
+func GenerateAccessToken(userID, role, sessionID string) (string, error) {
+	return generateToken(userID, role, sessionID, AccessTokenType, accessAudience, time.Now().Add(accessTokenTTL))
+}
+
+// GenerateRefreshToken leva um jti aleatório para que dois tokens da mesma sessão nunca sejam
+// iguais, já que a sessão guarda só o hash do último emitido.
+func GenerateRefreshToken(userID, role, sessionID string, expiresAt time.Time) (string, error) {
+	return generateToken(userID, role, sessionID, RefreshTokenType, refreshAudience, expiresAt)
+}
+
+func generateToken(userID, role, sessionID string, typ TokenType, audience string, expiresAt time.Time) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		Type:      typ,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Issuer:    jwtIssuer,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return keyring.Sign(claims)
 }
 
-func ValidateJWT(tokenStr string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+func ValidateAccessToken(tokenStr string) (*Claims, error) {
+	return validateToken(tokenStr, AccessTokenType, accessAudience)
+}
+
+func ValidateRefreshToken(tokenStr string) (*Claims, error) {
+	return validateToken(tokenStr, RefreshTokenType, refreshAudience)
+}
+
+func validateToken(tokenStr string, typ TokenType, audience string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, keyring.keyFunc,
+		jwt.WithValidMethods(keyring.methods()),
+		jwt.WithIssuer(jwtIssuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, ErrInvalidToken
+	}
+	if claims.Type != typ || claims.UserID == "" || claims.SessionID == "" {
+		return nil, ErrInvalidClaims
 	}
 	return claims, nil
 }
+
+// HashToken é o que fica salvo no lugar do refresh token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,311 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+const minRSAKeyBits = 2048
+
+var ErrNoSigningKey = errors.New("JWT_SIGNING_KEY or JWT_SECRET environment variable must be set")
+
+// Keyring guarda a chave que assina os tokens e todas as que ainda podem verificá-los.
+// O kid de cada chave assimétrica é o thumbprint RFC 7638, então o mesmo arquivo gera sempre
+// o mesmo kid aqui e em quem consome o JWKS.
+type Keyring struct {
+	signer *keyringKey
+	keys   map[string]*keyringKey
+	// segredo HS256 legado, aceito só para tokens sem kid e vazio quando há chave assimétrica,
+	// salvo com JWT_ACCEPT_LEGACY_HS256
+	secret []byte
+}
+
+type keyringKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+	jwk     JWK
+}
+
+// NewKeyring assina com signing quando houver e, sem ele, com o segredo HS256.
+func NewKeyring(signing crypto.PrivateKey, verification []crypto.PublicKey, secret []byte) (*Keyring, error) {
+	k := &Keyring{keys: map[string]*keyringKey{}, secret: secret}
+
+	if signing != nil {
+		signer, ok := signing.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported signing key type %T", signing)
+		}
+		key, err := newKeyringKey(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		key.private = signing
+		k.signer = key
+		k.keys[key.kid] = key
+	} else if len(secret) == 0 {
+		return nil, ErrNoSigningKey
+	}
+
+	for _, pub := range verification {
+		key, err := newKeyringKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := k.keys[key.kid]; !exists {
+			k.keys[key.kid] = key
+		}
+	}
+	return k, nil
+}
+
+func newKeyringKey(pub crypto.PublicKey) (*keyringKey, error) {
+	key := &keyringKey{public: pub}
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		if p.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must have at least %d bits", minRSAKeyBits)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.jwk = JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(p.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+		key.jwk = JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(p),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	kid, err := key.jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	key.kid = kid
+	key.jwk.Kid = kid
+	key.jwk.Use = "sig"
+	key.jwk.Alg = key.method.Alg()
+	return key, nil
+}
+
+// Thumbprint calcula o identificador da chave segundo a RFC 7638.
+func (k JWK) Thumbprint() (string, error) {
+	var members map[string]string
+	switch k.Kty {
+	case "RSA":
+		members = map[string]string{"e": k.E, "kty": k.Kty, "n": k.N}
+	case "OKP":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X}
+	default:
+		return "", fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+	// json.Marshal ordena as chaves do map, que é a forma canônica pedida pela RFC
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// LoadKeyringFromEnv lê a chave privada PEM de JWT_SIGNING_KEY ou JWT_SIGNING_KEY_FILE e as
+// chaves públicas ainda aceitas de JWT_VERIFICATION_KEYS (PEMs concatenados) ou
+// JWT_VERIFICATION_KEY_FILES (caminhos separados por vírgula). JWT_SECRET assina enquanto não
+// houver chave assimétrica; depois dela, tokens HS256 antigos só são aceitos com
+// JWT_ACCEPT_LEGACY_HS256=true, para a migração, e o JWT_SECRET pode ser removido.
+//
+// Para girar a chave, a nova vai para JWT_SIGNING_KEY e a pública da antiga para
+// JWT_VERIFICATION_KEYS até os tokens emitidos com ela expirarem.
+func LoadKeyringFromEnv() (*Keyring, error) {
+	signingPEM, err := pemFromEnv("JWT_SIGNING_KEY", "JWT_SIGNING_KEY_FILE")
+	if err != nil {
+		return nil, err
+	}
+	var signing crypto.PrivateKey
+	if len(signingPEM) > 0 {
+		if signing, err = parsePrivateKey(signingPEM); err != nil {
+			return nil, fmt.Errorf("JWT_SIGNING_KEY: %w", err)
+		}
+	}
+
+	verificationPEM := []byte(unescapePEM(os.Getenv("JWT_VERIFICATION_KEYS")))
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEY_FILES: %w", err)
+		}
+		verificationPEM = append(append(verificationPEM, '\n'), data...)
+	}
+	verification, err := parsePublicKeys(verificationPEM)
+	if err != nil {
+		return nil, fmt.Errorf("JWT_VERIFICATION_KEYS: %w", err)
+	}
+
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	if signing != nil && os.Getenv("JWT_ACCEPT_LEGACY_HS256") != "true" {
+		secret = nil
+	}
+	return NewKeyring(signing, verification, secret)
+}
+
+// SigningKeyConfigured indica se os tokens são assinados por chave assimétrica, caso em que o
+// JWT_SECRET deixa de ser obrigatório.
+func SigningKeyConfigured() bool {
+	return os.Getenv("JWT_SIGNING_KEY") != "" || os.Getenv("JWT_SIGNING_KEY_FILE") != ""
+}
+
+func pemFromEnv(valueVar, fileVar string) ([]byte, error) {
+	if value := os.Getenv(valueVar); value != "" {
+		return []byte(unescapePEM(value)), nil
+	}
+	if path := os.Getenv(fileVar); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fileVar, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// unescapePEM aceita PEM com "\n" literal, comum em variáveis de ambiente de uma linha só.
+func unescapePEM(value string) string {
+	return strings.ReplaceAll(value, `\n`, "\n")
+}
+
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func parsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return keys, nil
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		default:
+			err = fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+}
+
+// Sign assina com a chave atual e marca o kid no header.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	if k.signer == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+	token := jwt.NewWithClaims(k.signer.method, claims)
+	token.Header["kid"] = k.signer.kid
+	return token.SignedString(k.signer.private)
+}
+
+// keyFunc escolhe a chave pelo kid e exige o algoritmo daquela chave, o que impede usar uma
+// chave pública como segredo HMAC.
+func (k *Keyring) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if len(k.secret) > 0 && t.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return k.secret, nil
+		}
+		return nil, ErrUnknownKey
+	}
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", t.Method.Alg(), kid)
+	}
+	return key.public, nil
+}
+
+func (k *Keyring) methods() []string {
+	var methods []string
+	if len(k.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	for _, key := range k.keys {
+		if !slices.Contains(methods, key.method.Alg()) {
+			methods = append(methods, key.method.Alg())
+		}
+	}
+	return methods
+}
+
+// JWKS lista as chaves públicas, com a de assinatura primeiro. O segredo HS256 nunca aparece.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if k.signer != nil {
+		set.Keys = append(set.Keys, k.signer.jwk)
+	}
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		if k.signer == nil || kid != k.signer.kid {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		set.Keys = append(set.Keys, k.keys[kid].jwk)
+	}
+	return set
+}
+
+// JWKSHandler publica as chaves de verificação em /.well-known/jwks.json.
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	config.JSON(w, http.StatusOK, keyring.JWKS())
+}
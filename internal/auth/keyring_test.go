@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "legacy-secret-with-enough-entropy-123"
+
+func setSigningKeyEnv(t *testing.T) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate Ed25519 key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	t.Setenv("JWT_SIGNING_KEY", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	t.Setenv("JWT_SIGNING_KEY_FILE", "")
+}
+
+func legacyToken(t *testing.T) string {
+	t.Helper()
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		UserID:    "user-1",
+		Type:      AccessTokenType,
+		SessionID: "session-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			Audience:  jwt.ClaimStrings{accessAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("sign legacy token: %v", err)
+	}
+	return raw
+}
+
+func TestLoadKeyringFromEnvLegacyHS256(t *testing.T) {
+	tests := []struct {
+		name       string
+		signingKey bool
+		flag       string
+		accepted   bool
+	}{
+		{name: "secret only", accepted: true},
+		{name: "signing key drops HS256", signingKey: true},
+		{name: "signing key with legacy flag", signingKey: true, flag: "true", accepted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", testJWTSecret)
+			t.Setenv("JWT_SIGNING_KEY", "")
+			t.Setenv("JWT_SIGNING_KEY_FILE", "")
+			t.Setenv("JWT_ACCEPT_LEGACY_HS256", tt.flag)
+			if tt.signingKey {
+				setSigningKeyEnv(t)
+			}
+
+			kr, err := LoadKeyringFromEnv()
+			if err != nil {
+				t.Fatalf("LoadKeyringFromEnv: %v", err)
+			}
+			previous := keyring
+			keyring = kr
+			t.Cleanup(func() { keyring = previous })
+
+			_, err = ValidateAccessToken(legacyToken(t))
+			if tt.accepted && err != nil {
+				t.Fatalf("legacy token rejected: %v", err)
+			}
+			if !tt.accepted && !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("err = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestNewGoogleOAuthFromEnvStateSecret(t *testing.T) {
+	t.Setenv("GOOGLE_CLIENT_ID", "client-id")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "client-secret")
+	t.Setenv("GOOGLE_REDIRECT_URL", "http://localhost/auth/google/callback")
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("OAUTH_STATE_SECRET", "")
+
+	t.Run("falls back to JWT_SECRET without a signing key", func(t *testing.T) {
+		t.Setenv("JWT_SIGNING_KEY", "")
+		t.Setenv("JWT_SIGNING_KEY_FILE", "")
+		if _, err := NewGoogleOAuthFromEnv(); err != nil {
+			t.Fatalf("NewGoogleOAuthFromEnv: %v", err)
+		}
+	})
+
+	t.Run("requires OAUTH_STATE_SECRET with a signing key", func(t *testing.T) {
+		setSigningKeyEnv(t)
+		if _, err := NewGoogleOAuthFromEnv(); !errors.Is(err, ErrNoOAuthStateSecret) {
+			t.Fatalf("err = %v, want ErrNoOAuthStateSecret", err)
+		}
+		t.Setenv("OAUTH_STATE_SECRET", "state-secret")
+		if _, err := NewGoogleOAuthFromEnv(); err != nil {
+			t.Fatalf("NewGoogleOAuthFromEnv: %v", err)
+		}
+	})
+}
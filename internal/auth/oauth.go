@@ -1,13 +1,47 @@
 package auth
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
-	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
 )
 
+const (
+	// cookie que guarda o verificador PKCE entre o início do fluxo e o callback
+	OAUTH_VERIFIER_COOKIE_NAME = "oauth_verifier"
+	oauthStateTTL              = 10 * time.Minute
+	defaultGoogleAuthURL       = "https://accounts.google.com/o/oauth2/v2/auth"
+	defaultGoogleTokenURL      = "https://oauth2.googleapis.com/token"
+	defaultGoogleUserInfoURL   = "https://www.googleapis.com/oauth2/v2/userinfo"
+)
+
+var (
+	ErrOAuthNotConfigured = errors.New("google oauth is not configured")
+	ErrInvalidOAuthState  = errors.New("invalid or expired oauth state")
+	ErrOAuthExchange      = errors.New("failed to exchange google authorization code")
+	ErrEmailNotVerified   = errors.New("google account email is not verified")
+	ErrNoOAuthStateSecret = errors.New("OAUTH_STATE_SECRET environment variable must be set when JWT_SIGNING_KEY is used")
+)
+
+var googleScopes = []string{
+	"openid",
+	"email",
+	"profile",
+	"https://www.googleapis.com/auth/calendar",
+}
+
 type GoogleUserInfo struct {
 	ID            string `json:"id"`
 	Email         string `json:"email"`
@@ -16,6 +50,7 @@ type GoogleUserInfo struct {
 	VerifiedEmail bool   `json:"verified_email"`
 }
 
+// AuthResult é a identidade já confirmada pelo Google, com os tokens do Google criptografados.
 type AuthResult struct {
 	ProviderID   string
 	Username     string
@@ -25,57 +60,247 @@ type AuthResult struct {
 	RefreshToken string
 }
 
-type CallbackPayload struct {
-	User   GoogleUserInfo   `json:"user"`
-	Tokens GoogleTokenReply `json:"tokens"`
+// GoogleOAuthConfig permite apontar o fluxo para outro servidor, como um fake local em testes.
+type GoogleOAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	StateSecret  []byte
+	HTTPClient   *http.Client
 }
 
-type GoogleTokenReply struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+type GoogleOAuth struct {
+	oauth       *oauth2.Config
+	userInfoURL string
+	stateSecret []byte
+	httpClient  *http.Client
 }
 
-func GoogleCodeHandler(w http.ResponseWriter, r *http.Request) {
-	log := config.WithContext(r.Context())
+// OAuthStart é o que o handler precisa para redirecionar o navegador ao Google.
+type OAuthStart struct {
+	URL      string
+	Verifier string
+}
 
-	var payload CallbackPayload
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		log.WithError(err).Error("Payload inválido recebido do frontend")
-		http.Error(w, "invalid request body", http.StatusBadRequest)
-		return
+type oauthState struct {
+	Challenge string `json:"c"`
+	ReturnTo  string `json:"r,omitempty"`
+	ExpiresAt int64  `json:"e"`
+	Nonce     string `json:"n"`
+}
+
+func NewGoogleOAuth(cfg GoogleOAuthConfig) (*GoogleOAuth, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" || cfg.RedirectURL == "" || len(cfg.StateSecret) == 0 {
+		return nil, ErrOAuthNotConfigured
 	}
 
-	encryptedAccessToken := ""
-	encryptedRefreshToken := ""
+	endpoint := oauth2.Endpoint{
+		AuthURL:   defaultGoogleAuthURL,
+		TokenURL:  defaultGoogleTokenURL,
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+	if cfg.AuthURL != "" {
+		endpoint.AuthURL = cfg.AuthURL
+	}
+	if cfg.TokenURL != "" {
+		endpoint.TokenURL = cfg.TokenURL
+	}
+	userInfoURL := cfg.UserInfoURL
+	if userInfoURL == "" {
+		userInfoURL = defaultGoogleUserInfoURL
+	}
+
+	return &GoogleOAuth{
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     endpoint,
+			Scopes:       googleScopes,
+		},
+		userInfoURL: userInfoURL,
+		stateSecret: cfg.StateSecret,
+		httpClient:  cfg.HTTPClient,
+	}, nil
+}
 
-	if payload.Tokens.AccessToken != "" {
-		if enc, err := config.Encrypt(payload.Tokens.AccessToken); err == nil {
-			encryptedAccessToken = enc
+// NewGoogleOAuthFromEnv lê GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET e GOOGLE_REDIRECT_URL.
+// GOOGLE_AUTH_URL, GOOGLE_TOKEN_URL e GOOGLE_USERINFO_URL são opcionais; o state é assinado
+// com OAUTH_STATE_SECRET. Só sem JWT_SIGNING_KEY ele cai para o JWT_SECRET, para que o segredo
+// HS256 possa ser aposentado sem quebrar o login.
+func NewGoogleOAuthFromEnv() (*GoogleOAuth, error) {
+	secret := os.Getenv("OAUTH_STATE_SECRET")
+	if secret == "" {
+		if SigningKeyConfigured() {
+			return nil, ErrNoOAuthStateSecret
 		}
+		secret = os.Getenv("JWT_SECRET")
+	}
+	return NewGoogleOAuth(GoogleOAuthConfig{
+		ClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
+		AuthURL:      os.Getenv("GOOGLE_AUTH_URL"),
+		TokenURL:     os.Getenv("GOOGLE_TOKEN_URL"),
+		UserInfoURL:  os.Getenv("GOOGLE_USERINFO_URL"),
+		StateSecret:  []byte(secret),
+	})
+}
+
+func (g *GoogleOAuth) context(ctx context.Context) context.Context {
+	if g.httpClient != nil {
+		return context.WithValue(ctx, oauth2.HTTPClient, g.httpClient)
+	}
+	return ctx
+}
+
+// Start gera o verificador PKCE e a URL de consentimento. O state assinado carrega o desafio
+// S256, então só o navegador que tem o verificador no cookie consegue concluir o fluxo.
+func (g *GoogleOAuth) Start(returnTo string) (*OAuthStart, error) {
+	verifier := oauth2.GenerateVerifier()
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	state, err := g.signState(oauthState{
+		Challenge: oauth2.S256ChallengeFromVerifier(verifier),
+		ReturnTo:  SafeReturnPath(returnTo),
+		ExpiresAt: time.Now().Add(oauthStateTTL).Unix(),
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := g.oauth.AuthCodeURL(state,
+		oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("prompt", "consent"),
+		oauth2.S256ChallengeOption(verifier),
+	)
+	return &OAuthStart{URL: url, Verifier: verifier}, nil
+}
+
+// Exchange valida o state, troca o código no servidor e busca o perfil com o token obtido.
+// Devolve também o caminho do frontend guardado no state.
+func (g *GoogleOAuth) Exchange(ctx context.Context, code, state, verifier string) (*AuthResult, string, error) {
+	st, err := g.verifyState(state)
+	if err != nil {
+		return nil, "", err
+	}
+	if verifier == "" || oauth2.S256ChallengeFromVerifier(verifier) != st.Challenge {
+		return nil, "", ErrInvalidOAuthState
 	}
+	if code == "" {
+		return nil, "", ErrOAuthExchange
+	}
+
+	ctx = g.context(ctx)
+	token, err := g.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrOAuthExchange, err)
+	}
+
+	info, err := g.fetchUserInfo(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+	if info.ID == "" || info.Email == "" {
+		return nil, "", fmt.Errorf("%w: userinfo without id or email", ErrOAuthExchange)
+	}
+	if !info.VerifiedEmail {
+		return nil, "", ErrEmailNotVerified
+	}
+
+	result, err := newAuthResult(info, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return result, st.ReturnTo, nil
+}
+
+func (g *GoogleOAuth) fetchUserInfo(ctx context.Context, token *oauth2.Token) (*GoogleUserInfo, error) {
+	resp, err := g.oauth.Client(ctx, token).Get(g.userInfoURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthExchange, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: userinfo returned status %d", ErrOAuthExchange, resp.StatusCode)
+	}
+	var info GoogleUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthExchange, err)
+	}
+	return &info, nil
+}
 
-	if payload.Tokens.RefreshToken != "" {
-		if enc, err := config.Encrypt(payload.Tokens.RefreshToken); err == nil {
-			encryptedRefreshToken = enc
+func newAuthResult(info *GoogleUserInfo, token *oauth2.Token) (*AuthResult, error) {
+	result := &AuthResult{
+		ProviderID: info.ID,
+		Username:   info.Name,
+		Email:      info.Email,
+		Picture:    info.Picture,
+	}
+	if token.AccessToken != "" {
+		enc, err := config.Encrypt(token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		result.AccessToken = enc
+	}
+	if token.RefreshToken != "" {
+		enc, err := config.Encrypt(token.RefreshToken)
+		if err != nil {
+			return nil, err
 		}
+		result.RefreshToken = enc
 	}
+	return result, nil
+}
 
-	authResult := &AuthResult{
-		ProviderID:   payload.User.ID,
-		Username:     payload.User.Name,
-		Email:        payload.User.Email,
-		Picture:      payload.User.Picture,
-		AccessToken:  encryptedAccessToken,
-		RefreshToken: encryptedRefreshToken,
+func (g *GoogleOAuth) signState(st oauthState) (string, error) {
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return "", err
 	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + g.stateMAC(body), nil
+}
 
-	log.WithFields(logrus.Fields{
-		"email":   payload.User.Email,
-		"user_id": payload.User.ID,
-	}).Info("Usuário autenticado com sucesso via Google")
+func (g *GoogleOAuth) verifyState(state string) (*oauthState, error) {
+	body, mac, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(g.stateMAC(body))) {
+		return nil, ErrInvalidOAuthState
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidOAuthState
+	}
+	var st oauthState
+	if err := json.Unmarshal(payload, &st); err != nil {
+		return nil, ErrInvalidOAuthState
+	}
+	if time.Now().Unix() > st.ExpiresAt {
+		return nil, ErrInvalidOAuthState
+	}
+	return &st, nil
+}
 
-	json.NewEncoder(w).Encode(map[string]any{
-		"user":    authResult,
-		"message": "Google login successful",
-	})
+func (g *GoogleOAuth) stateMAC(body string) string {
+	m := hmac.New(sha256.New, g.stateSecret)
+	m.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// SafeReturnPath aceita só caminhos relativos ao frontend, evitando redirecionamento aberto.
+func SafeReturnPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.Contains(path, "\\") {
+		return "/"
+	}
+	return path
 }
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// fakeGoogle faz o papel dos endpoints de token e userinfo do Google.
+type fakeGoogle struct {
+	server        *httptest.Server
+	verifiedEmail bool
+	exchanges     atomic.Int32
+	lastVerifier  string
+}
+
+func newFakeGoogle(t *testing.T) *fakeGoogle {
+	t.Helper()
+	f := &fakeGoogle{verifiedEmail: true}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		f.exchanges.Add(1)
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "auth-code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		f.lastVerifier = r.Form.Get("code_verifier")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "google-access",
+			"refresh_token": "google-refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer google-access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(GoogleUserInfo{
+			ID:            "google-123",
+			Email:         "ana@example.com",
+			Name:          "Ana",
+			VerifiedEmail: f.verifiedEmail,
+		})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeGoogle) oauth(t *testing.T, secret string) *GoogleOAuth {
+	t.Helper()
+	g, err := NewGoogleOAuth(GoogleOAuthConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost/auth/google/callback",
+		AuthURL:      f.server.URL + "/auth",
+		TokenURL:     f.server.URL + "/token",
+		UserInfoURL:  f.server.URL + "/userinfo",
+		StateSecret:  []byte(secret),
+		HTTPClient:   f.server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("NewGoogleOAuth: %v", err)
+	}
+	return g
+}
+
+func startFlow(t *testing.T, g *GoogleOAuth, returnTo string) (state, verifier string) {
+	t.Helper()
+	start, err := g.Start(returnTo)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	u, err := url.Parse(start.URL)
+	if err != nil {
+		t.Fatalf("parse auth URL: %v", err)
+	}
+	if u.Query().Get("code_challenge") != oauth2.S256ChallengeFromVerifier(start.Verifier) {
+		t.Fatalf("auth URL does not carry the S256 challenge of the verifier")
+	}
+	return u.Query().Get("state"), start.Verifier
+}
+
+func initTestCrypto(t *testing.T) {
+	t.Helper()
+	t.Setenv("CRYPTO_KEY", "0123456789abcdef0123456789abcdef")
+	config.InitCrypto()
+}
+
+func TestGoogleOAuthExchange(t *testing.T) {
+	initTestCrypto(t)
+	f := newFakeGoogle(t)
+	g := f.oauth(t, "state-secret")
+
+	state, verifier := startFlow(t, g, "/projects?tab=1")
+	result, returnTo, err := g.Exchange(t.Context(), "auth-code", state, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if f.lastVerifier != verifier {
+		t.Errorf("token endpoint got verifier %q, want %q", f.lastVerifier, verifier)
+	}
+	if returnTo != "/projects?tab=1" {
+		t.Errorf("returnTo = %q", returnTo)
+	}
+	if result.ProviderID != "google-123" || result.Email != "ana@example.com" {
+		t.Errorf("unexpected identity %+v", result)
+	}
+	if result.AccessToken == "" || result.AccessToken == "google-access" {
+		t.Errorf("google access token should be stored encrypted, got %q", result.AccessToken)
+	}
+}
+
+func TestGoogleOAuthRejectsTamperedState(t *testing.T) {
+	f := newFakeGoogle(t)
+	g := f.oauth(t, "state-secret")
+	state, verifier := startFlow(t, g, "/projects")
+
+	body, mac, _ := strings.Cut(state, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(body)
+	var st oauthState
+	if err := json.Unmarshal(payload, &st); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	st.ReturnTo = "/admin"
+	payload, _ = json.Marshal(st)
+	edited := base64.RawURLEncoding.EncodeToString(payload) + "." + mac
+
+	forged, err := f.oauth(t, "other-secret").signState(st)
+	if err != nil {
+		t.Fatalf("signState: %v", err)
+	}
+
+	for name, s := range map[string]string{
+		"edited payload": edited,
+		"other secret":   forged,
+		"missing mac":    body,
+		"empty":          "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := g.Exchange(t.Context(), "auth-code", s, verifier); !errors.Is(err, ErrInvalidOAuthState) {
+				t.Fatalf("err = %v, want ErrInvalidOAuthState", err)
+			}
+		})
+	}
+	if n := f.exchanges.Load(); n != 0 {
+		t.Errorf("token endpoint called %d times with an invalid state", n)
+	}
+}
+
+func TestGoogleOAuthRejectsExpiredState(t *testing.T) {
+	f := newFakeGoogle(t)
+	g := f.oauth(t, "state-secret")
+
+	verifier := oauth2.GenerateVerifier()
+	state, err := g.signState(oauthState{
+		Challenge: oauth2.S256ChallengeFromVerifier(verifier),
+		ExpiresAt: time.Now().Add(-time.Second).Unix(),
+		Nonce:     "n",
+	})
+	if err != nil {
+		t.Fatalf("signState: %v", err)
+	}
+	if _, _, err := g.Exchange(t.Context(), "auth-code", state, verifier); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Fatalf("err = %v, want ErrInvalidOAuthState", err)
+	}
+	if n := f.exchanges.Load(); n != 0 {
+		t.Errorf("token endpoint called %d times with an expired state", n)
+	}
+}
+
+func TestGoogleOAuthRejectsVerifierMismatch(t *testing.T) {
+	f := newFakeGoogle(t)
+	g := f.oauth(t, "state-secret")
+	state, _ := startFlow(t, g, "/")
+
+	for name, verifier := range map[string]string{
+		"other verifier": oauth2.GenerateVerifier(),
+		"no cookie":      "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := g.Exchange(t.Context(), "auth-code", state, verifier); !errors.Is(err, ErrInvalidOAuthState) {
+				t.Fatalf("err = %v, want ErrInvalidOAuthState", err)
+			}
+		})
+	}
+	if n := f.exchanges.Load(); n != 0 {
+		t.Errorf("token endpoint called %d times with a mismatched verifier", n)
+	}
+}
+
+func TestGoogleOAuthRejectsUnverifiedEmail(t *testing.T) {
+	f := newFakeGoogle(t)
+	f.verifiedEmail = false
+	g := f.oauth(t, "state-secret")
+	state, verifier := startFlow(t, g, "/")
+
+	if _, _, err := g.Exchange(t.Context(), "auth-code", state, verifier); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("err = %v, want ErrEmailNotVerified", err)
+	}
+}
+
+func TestSafeReturnPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"/projects":           "/projects",
+		"/tasks?view=week#x":  "/tasks?view=week#x",
+		"":                    "/",
+		"projects":            "/",
+		"//evil.example.com":  "/",
+		"/\\evil.example.com": "/",
+		"https://evil.com/":   "/",
+		"javascript:alert(1)": "/",
+	} {
+		if got := SafeReturnPath(path); got != want {
+			t.Errorf("SafeReturnPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleUser  = "USER"
+	RoleAdmin = "ADMIN"
+)
+
+type Permission string
+
+const (
+	// CRUD dos próprios dados: projetos, tarefas e estudos
+	PermissionManageOwnData Permission = "own_data:manage"
+	// listar e revogar as próprias sessões de login
+	PermissionManageSessions Permission = "sessions:manage"
+	PermissionManageUsers    Permission = "users:manage"
+)
+
+// rolePermissions é a matriz de permissões. Um papel fora dela não tem permissão nenhuma.
+var rolePermissions = map[string][]Permission{
+	RoleUser: {
+		PermissionManageOwnData,
+		PermissionManageSessions,
+	},
+	RoleAdmin: {
+		PermissionManageOwnData,
+		PermissionManageSessions,
+		PermissionManageUsers,
+	},
+}
+
+func HasPermission(role string, permission Permission) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// RequireRole aceita qualquer um dos papéis informados. Deve vir depois do AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return authorize(func(claims *ClaimsFromContext) bool {
+		return slices.Contains(roles, claims.Role)
+	})
+}
+
+// RequirePermission consulta a matriz de permissões. Deve vir depois do AuthMiddleware.
+func RequirePermission(permission Permission) func(http.Handler) http.Handler {
+	return authorize(func(claims *ClaimsFromContext) bool {
+		return HasPermission(claims.Role, permission)
+	})
+}
+
+func authorize(allowed func(*ClaimsFromContext) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := GetUserClaimsFromContext(r.Context())
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if !allowed(claims) {
+				log.Printf("[Authorize] Acesso negado para o usuário %s com papel %s em %s", claims.UserID, claims.Role, r.URL.Path)
+				http.Error(w, ErrInsufficientPermissions.Error(), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsOwner é a regra única de acesso a recursos de usuário: só o dono lê ou altera. Os
+// serviços chamam aqui em vez de comparar IDs, para a regra mudar num lugar só.
+func IsOwner(userID string, ownerID uuid.UUID) bool {
+	return userID != "" && userID == ownerID.String()
+}
@@ -24,7 +24,7 @@ func Connect(ctx context.Context, dsn string) error {
 		sqlDB, errOpen := gorm.Open(postgres.New(postgres.Config{
 			DSN:                  dsn,
 			PreferSimpleProtocol: true,
-		}), &gorm.Config{})
+		}), &gorm.Config{TranslateError: true})
 		if errOpen != nil {
 			log.WithError(errOpen).Errorf("Falha inicial ao abrir a conexão com o banco de dados")
 			err = errOpen
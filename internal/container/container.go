@@ -5,9 +5,14 @@ import (
 	"log"
 	"os"
 
+	"github.com/saulo-duarte/chronos-lambda/internal/assessment"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/flashcard"
+	"github.com/saulo-duarte/chronos-lambda/internal/googleservice"
+	"github.com/saulo-duarte/chronos-lambda/internal/milestone"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysession "github.com/saulo-duarte/chronos-lambda/internal/study_session"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
 	"github.com/saulo-duarte/chronos-lambda/internal/task"
@@ -17,9 +22,13 @@ import (
 type Container struct {
 	UserContainer         *user.UserContainer
 	ProjectContainer      *project.ProjectContainer
+	MilestoneContainer    *milestone.MilestoneContainer
 	TaskContainer         *task.TaskContainer
 	StudySubjectContainer *studysubject.StudySubjectContainer
 	StudyTopicContainer   *studytopic.StudyTopicContainer
+	FlashcardContainer    *flashcard.FlashcardContainer
+	AssessmentContainer   *assessment.AssessmentContainer
+	StudySessionContainer *studysession.StudySessionContainer
 }
 
 func New() *Container {
@@ -33,22 +42,34 @@ func New() *Container {
 	}
 
 	userContainer := user.NewUserContainer(config.DB)
+	auth.SetSessionValidator(user.NewSessionValidator(userContainer.Repo))
 	projectContainer := project.NewProjectContainer(config.DB)
+	milestoneContainer := milestone.NewMilestoneContainer(config.DB, projectContainer.Service)
 	studySubjectContainer := studysubject.NewStudySubjectContainer(config.DB)
 	studyTopicContainer := studytopic.NewStudyTopicContainer(config.DB)
+	flashcardContainer := flashcard.NewFlashcardContainer(config.DB, studyTopicContainer.Repo)
+	assessmentContainer := assessment.NewAssessmentContainer(config.DB, studyTopicContainer.Repo)
+	studySessionContainer := studysession.NewStudySessionContainer(config.DB, studyTopicContainer.Repo)
 
 	taskContainer := task.NewTaskContainer(
 		config.DB,
 		projectContainer.Service,
 		studyTopicContainer.Repo,
 		userContainer.Repo,
+		milestoneContainer.Repo,
+		flashcardContainer.Repo,
+		googleservice.NewGoogleBusyProvider(),
 	)
 
 	return &Container{
 		UserContainer:         userContainer,
 		ProjectContainer:      projectContainer,
+		MilestoneContainer:    milestoneContainer,
 		TaskContainer:         taskContainer,
 		StudySubjectContainer: studySubjectContainer,
 		StudyTopicContainer:   studyTopicContainer,
+		FlashcardContainer:    flashcardContainer,
+		AssessmentContainer:   assessmentContainer,
+		StudySessionContainer: studySessionContainer,
 	}
 }
@@ -0,0 +1,23 @@
+package flashcard
+
+import (
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"gorm.io/gorm"
+)
+
+type FlashcardContainer struct {
+	Handler *Handler
+	Repo    FlashcardRepository
+}
+
+func NewFlashcardContainer(db *gorm.DB, topicRepo studytopic.StudyTopicRepository) *FlashcardContainer {
+	repo := NewRepository(db)
+	service := NewService(repo, topicRepo, studysubject.NewRepository(db))
+	handler := NewHandler(service)
+
+	return &FlashcardContainer{
+		Handler: handler,
+		Repo:    repo,
+	}
+}
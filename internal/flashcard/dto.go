@@ -0,0 +1,85 @@
+package flashcard
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	maxBulkFlashcards   = 200
+	defaultSessionLimit = 20
+	maxSessionLimit     = 100
+	// intervalo a partir do qual um cartão é considerado maduro
+	matureIntervalDays = 21
+)
+
+var (
+	ErrInvalidFlashcard = errors.New("front and back cannot be empty")
+	ErrBulkLimit        = errors.New("bulk create accepts between 1 and 200 flashcards")
+)
+
+type FlashcardDTO struct {
+	Front string   `json:"front"`
+	Back  string   `json:"back"`
+	Tags  []string `json:"tags"`
+}
+
+func (dto *FlashcardDTO) Validate() error {
+	if strings.TrimSpace(dto.Front) == "" || strings.TrimSpace(dto.Back) == "" {
+		return ErrInvalidFlashcard
+	}
+	return nil
+}
+
+// normalizedTags remove tags vazias e repetidas, preservando a ordem.
+func (dto *FlashcardDTO) normalizedTags() []string {
+	tags := make([]string, 0, len(dto.Tags))
+	seen := make(map[string]bool, len(dto.Tags))
+	for _, tag := range dto.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+type BulkFlashcardDTO struct {
+	Flashcards []FlashcardDTO `json:"flashcards"`
+}
+
+func (dto *BulkFlashcardDTO) Validate() error {
+	if len(dto.Flashcards) == 0 || len(dto.Flashcards) > maxBulkFlashcards {
+		return ErrBulkLimit
+	}
+	for i := range dto.Flashcards {
+		if err := dto.Flashcards[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ReviewDTO struct {
+	Grade *int `json:"grade"`
+}
+
+type SessionOptions struct {
+	TopicID  string
+	Limit    int
+	NewLimit int
+}
+
+func (o *SessionOptions) normalize() {
+	if o.Limit <= 0 {
+		o.Limit = defaultSessionLimit
+	}
+	if o.Limit > maxSessionLimit {
+		o.Limit = maxSessionLimit
+	}
+	if o.NewLimit <= 0 || o.NewLimit > o.Limit {
+		o.NewLimit = o.Limit
+	}
+}
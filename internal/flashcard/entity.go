@@ -0,0 +1,51 @@
+package flashcard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+type Flashcard struct {
+	ID      uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Front   string    `json:"front"`
+	Back    string    `json:"back"`
+	Tags    []string  `gorm:"serializer:json" json:"tags"`
+	TopicID uuid.UUID `gorm:"column:topic_id;not null;index" json:"topic_id"`
+	UserID  uuid.UUID `gorm:"column:user_id;not null" json:"user_id"`
+	util.SRSState
+	LastGrade      *int       `json:"last_grade"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	NextReviewAt   *time.Time `gorm:"type:date;index" json:"next_review_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsNew indica um cartão que ainda não foi revisado.
+func (f *Flashcard) IsNew() bool {
+	return f.NextReviewAt == nil
+}
+
+// DeckStats resume o baralho de uma matéria, no total e por tópico.
+type DeckStats struct {
+	SubjectID uuid.UUID    `json:"subject_id"`
+	Total     int64        `json:"total"`
+	New       int64        `json:"new"`
+	Due       int64        `json:"due"`
+	Learning  int64        `json:"learning"`
+	Mature    int64        `json:"mature"`
+	AvgEase   float64      `json:"average_ease"`
+	Topics    []TopicStats `json:"topics"`
+}
+
+type TopicStats struct {
+	TopicID   uuid.UUID `json:"topic_id"`
+	TopicName string    `json:"topic_name"`
+	Total     int64     `json:"total"`
+	New       int64     `json:"new"`
+	Due       int64     `json:"due"`
+	Learning  int64     `json:"learning"`
+	Mature    int64     `json:"mature"`
+	AvgEase   float64   `json:"average_ease"`
+}
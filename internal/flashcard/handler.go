@@ -0,0 +1,195 @@
+package flashcard
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+type Handler struct {
+	service FlashcardService
+}
+
+func NewHandler(s FlashcardService) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) CreateFlashcard(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload FlashcardDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	card, err := h.service.CreateFlashcard(r.Context(), chi.URLParam(r, "studyTopicId"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error creating flashcard")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, card)
+}
+
+func (h *Handler) BulkCreateFlashcards(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload BulkFlashcardDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cards, err := h.service.BulkCreateFlashcards(r.Context(), chi.URLParam(r, "studyTopicId"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error bulk creating flashcards")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, map[string]interface{}{
+		"count":      len(cards),
+		"flashcards": cards,
+	})
+}
+
+func (h *Handler) ListFlashcards(w http.ResponseWriter, r *http.Request) {
+	cards, err := h.service.ListFlashcardsByTopic(r.Context(), chi.URLParam(r, "studyTopicId"))
+	if err != nil {
+		writeError(w, r, err, "Error listing flashcards")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":      len(cards),
+		"flashcards": cards,
+	})
+}
+
+func (h *Handler) GetFlashcard(w http.ResponseWriter, r *http.Request) {
+	card, err := h.service.GetFlashcard(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error fetching flashcard")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, card)
+}
+
+func (h *Handler) UpdateFlashcard(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload FlashcardDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	card, err := h.service.UpdateFlashcard(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error updating flashcard")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, card)
+}
+
+func (h *Handler) DeleteFlashcard(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteFlashcard(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err, "Error deleting flashcard")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "flashcard deleted successfully",
+	})
+}
+
+func (h *Handler) ReviewFlashcard(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ReviewDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if payload.Grade == nil {
+		http.Error(w, "grade is required", http.StatusBadRequest)
+		return
+	}
+
+	card, err := h.service.ReviewFlashcard(r.Context(), chi.URLParam(r, "id"), *payload.Grade)
+	if err != nil {
+		writeError(w, r, err, "Error reviewing flashcard")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, card)
+}
+
+func (h *Handler) GetReviewSession(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := SessionOptions{TopicID: query.Get("topic_id")}
+
+	var err error
+	if raw := query.Get("limit"); raw != "" {
+		if opts.Limit, err = strconv.Atoi(raw); err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	if raw := query.Get("new_limit"); raw != "" {
+		if opts.NewLimit, err = strconv.Atoi(raw); err != nil {
+			http.Error(w, "new_limit must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cards, err := h.service.GetReviewSession(r.Context(), chi.URLParam(r, "studySubjectId"), opts)
+	if err != nil {
+		writeError(w, r, err, "Error building flashcard review session")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":      len(cards),
+		"flashcards": cards,
+	})
+}
+
+func (h *Handler) GetDeckStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.GetDeckStats(r.Context(), chi.URLParam(r, "studySubjectId"))
+	if err != nil {
+		writeError(w, r, err, "Error computing flashcard deck stats")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, stats)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrFlashcardNotFound):
+		http.Error(w, "flashcard not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudyTopicNotFound):
+		http.Error(w, "study topic not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidFlashcard), errors.Is(err, ErrBulkLimit), errors.Is(err, util.ErrInvalidRecallGrade):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
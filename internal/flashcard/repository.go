@@ -0,0 +1,131 @@
+package flashcard
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FlashcardRepository interface {
+	Create(f *Flashcard) error
+	CreateBatch(cards []*Flashcard) error
+	GetByID(id string) (*Flashcard, error)
+	ListByTopic(topicID uuid.UUID) ([]*Flashcard, error)
+	ListByTopics(topicIDs []uuid.UUID) ([]*Flashcard, error)
+	Update(f *Flashcard) error
+	Delete(id string) error
+	ListDue(subjectID uuid.UUID, topicID *uuid.UUID, before time.Time, limit int) ([]*Flashcard, error)
+	ListNew(subjectID uuid.UUID, topicID *uuid.UUID, limit int) ([]*Flashcard, error)
+	GetTopicStats(subjectID uuid.UUID, before time.Time) ([]TopicStats, error)
+}
+
+type flashcardRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) FlashcardRepository {
+	return &flashcardRepository{db: db}
+}
+
+func (r *flashcardRepository) Create(f *Flashcard) error {
+	return r.db.Create(f).Error
+}
+
+// CreateBatch insere todos os cartões numa única transação.
+func (r *flashcardRepository) CreateBatch(cards []*Flashcard) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&cards).Error
+	})
+}
+
+func (r *flashcardRepository) GetByID(id string) (*Flashcard, error) {
+	var card Flashcard
+	if err := r.db.First(&card, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (r *flashcardRepository) ListByTopic(topicID uuid.UUID) ([]*Flashcard, error) {
+	var cards []*Flashcard
+	if err := r.db.Where("topic_id = ?", topicID).Order("created_at").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (r *flashcardRepository) ListByTopics(topicIDs []uuid.UUID) ([]*Flashcard, error) {
+	var cards []*Flashcard
+	if len(topicIDs) == 0 {
+		return cards, nil
+	}
+	if err := r.db.Where("topic_id IN ?", topicIDs).Order("created_at").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (r *flashcardRepository) Update(f *Flashcard) error {
+	return r.db.Save(f).Error
+}
+
+func (r *flashcardRepository) Delete(id string) error {
+	return r.db.Delete(&Flashcard{}, "id = ?", id).Error
+}
+
+func (r *flashcardRepository) deckQuery(subjectID uuid.UUID, topicID *uuid.UUID) *gorm.DB {
+	q := r.db.Model(&Flashcard{}).
+		Joins("JOIN study_topics st ON st.id = flashcards.topic_id").
+		Where("st.subject_id = ?", subjectID)
+	if topicID != nil {
+		q = q.Where("flashcards.topic_id = ?", *topicID)
+	}
+	return q
+}
+
+// ListDue devolve os cartões vencidos, do mais atrasado para o mais recente.
+func (r *flashcardRepository) ListDue(subjectID uuid.UUID, topicID *uuid.UUID, before time.Time, limit int) ([]*Flashcard, error) {
+	var cards []*Flashcard
+	err := r.deckQuery(subjectID, topicID).
+		Where("flashcards.next_review_at < ?", before).
+		Order("flashcards.next_review_at, st.position, flashcards.created_at").
+		Limit(limit).
+		Find(&cards).Error
+	return cards, err
+}
+
+// ListNew devolve os cartões nunca revisados, na ordem dos tópicos.
+func (r *flashcardRepository) ListNew(subjectID uuid.UUID, topicID *uuid.UUID, limit int) ([]*Flashcard, error) {
+	var cards []*Flashcard
+	err := r.deckQuery(subjectID, topicID).
+		Where("flashcards.next_review_at IS NULL").
+		Order("st.position, flashcards.created_at").
+		Limit(limit).
+		Find(&cards).Error
+	return cards, err
+}
+
+// GetTopicStats agrega os cartões de cada tópico da matéria numa única consulta.
+func (r *flashcardRepository) GetTopicStats(subjectID uuid.UUID, before time.Time) ([]TopicStats, error) {
+	var stats []TopicStats
+	err := r.db.Table("study_topics st").
+		Select(`st.id AS topic_id, st.name AS topic_name,
+			COUNT(f.id) AS total,
+			COUNT(f.id) FILTER (WHERE f.next_review_at IS NULL) AS "new",
+			COUNT(f.id) FILTER (WHERE f.next_review_at < ?) AS due,
+			COUNT(f.id) FILTER (WHERE f.next_review_at IS NOT NULL AND f.interval_days < ?) AS learning,
+			COUNT(f.id) FILTER (WHERE f.interval_days >= ?) AS mature,
+			COALESCE(AVG(f.ease_factor) FILTER (WHERE f.next_review_at IS NOT NULL), 0) AS avg_ease`,
+			before, matureIntervalDays, matureIntervalDays).
+		Joins("LEFT JOIN flashcards f ON f.topic_id = st.id").
+		Where("st.subject_id = ?", subjectID).
+		Group("st.id, st.name, st.position").
+		Order("st.position").
+		Scan(&stats).Error
+	return stats, err
+}
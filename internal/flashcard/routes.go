@@ -0,0 +1,34 @@
+package flashcard
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+)
+
+func Routes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Get("/{id}", h.GetFlashcard)
+	r.Put("/{id}", h.UpdateFlashcard)
+	r.Delete("/{id}", h.DeleteFlashcard)
+	r.Post("/{id}/reviews", h.ReviewFlashcard)
+
+	return r
+}
+
+// TopicRoutes expõe os cartões de um tópico, montado em /study-topics/{studyTopicId}/flashcards.
+func TopicRoutes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Post("/", h.CreateFlashcard)
+	r.Post("/bulk", h.BulkCreateFlashcards)
+	r.Get("/", h.ListFlashcards)
+
+	return r
+}
@@ -0,0 +1,399 @@
+package flashcard
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrFlashcardNotFound    = errors.New("flashcard not found")
+	ErrStudyTopicNotFound   = studytopic.ErrStudyTopicNotFound
+	ErrStudySubjectNotFound = studysubject.ErrStudySubjectNotFound
+	ErrUnauthorized         = errors.New("unauthorized")
+)
+
+type FlashcardService interface {
+	CreateFlashcard(ctx context.Context, topicID string, dto *FlashcardDTO) (*Flashcard, error)
+	BulkCreateFlashcards(ctx context.Context, topicID string, dto *BulkFlashcardDTO) ([]*Flashcard, error)
+	GetFlashcard(ctx context.Context, id string) (*Flashcard, error)
+	ListFlashcardsByTopic(ctx context.Context, topicID string) ([]*Flashcard, error)
+	UpdateFlashcard(ctx context.Context, id string, dto *FlashcardDTO) (*Flashcard, error)
+	DeleteFlashcard(ctx context.Context, id string) error
+	ReviewFlashcard(ctx context.Context, id string, grade int) (*Flashcard, error)
+	GetReviewSession(ctx context.Context, subjectID string, opts SessionOptions) ([]*Flashcard, error)
+	GetDeckStats(ctx context.Context, subjectID string) (*DeckStats, error)
+}
+
+type flashcardService struct {
+	repo        FlashcardRepository
+	topicRepo   studytopic.StudyTopicRepository
+	subjectRepo studysubject.StudySubjectRepository
+}
+
+func NewService(repo FlashcardRepository, topicRepo studytopic.StudyTopicRepository, subjectRepo studysubject.StudySubjectRepository) FlashcardService {
+	return &flashcardService{repo: repo, topicRepo: topicRepo, subjectRepo: subjectRepo}
+}
+
+func currentUserID(ctx context.Context, log logrus.FieldLogger, action string) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("Attempt to %s without authentication", action)
+		return uuid.Nil, ErrUnauthorized
+	}
+	return uuid.MustParse(claims.UserID), nil
+}
+
+// getOwnedTopic garante que o tópico existe e pertence ao usuário autenticado.
+func (s *flashcardService) getOwnedTopic(ctx context.Context, topicID, action string) (*studytopic.StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(topicID); err != nil {
+		return nil, ErrStudyTopicNotFound
+	}
+
+	topic, err := s.topicRepo.GetByID(topicID)
+	if err != nil {
+		log.WithError(err).Error("Error fetching study topic for flashcards")
+		return nil, err
+	}
+	if topic == nil {
+		return nil, ErrStudyTopicNotFound
+	}
+	if !auth.IsOwner(userID.String(), topic.UserID) {
+		log.WithFields(logrus.Fields{
+			"topic_id": topic.ID,
+			"user_id":  userID,
+		}).Warnf("User attempted to %s on another user's topic", action)
+		return nil, ErrUnauthorized
+	}
+	return topic, nil
+}
+
+func (s *flashcardService) getOwnedSubject(ctx context.Context, subjectID, action string) (*studysubject.StudySubject, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(subjectID); err != nil {
+		return nil, ErrStudySubjectNotFound
+	}
+
+	subject, err := s.subjectRepo.GetByID(subjectID)
+	if err != nil {
+		log.WithError(err).Error("Error fetching study subject for flashcards")
+		return nil, err
+	}
+	if subject == nil {
+		return nil, ErrStudySubjectNotFound
+	}
+	if !auth.IsOwner(userID.String(), subject.UserID) {
+		log.WithFields(logrus.Fields{
+			"subject_id": subject.ID,
+			"user_id":    userID,
+		}).Warnf("User attempted to %s on another user's subject", action)
+		return nil, ErrUnauthorized
+	}
+	return subject, nil
+}
+
+func (s *flashcardService) getOwnedFlashcard(ctx context.Context, id, action string) (*Flashcard, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrFlashcardNotFound
+	}
+
+	card, err := s.repo.GetByID(id)
+	if err != nil {
+		log.WithError(err).Error("Error fetching flashcard by ID")
+		return nil, err
+	}
+	if card == nil {
+		return nil, ErrFlashcardNotFound
+	}
+	if !auth.IsOwner(userID.String(), card.UserID) {
+		log.WithFields(logrus.Fields{
+			"flashcard_id": card.ID,
+			"user_id":      userID,
+		}).Warnf("User attempted to %s another user's flashcard", action)
+		return nil, ErrUnauthorized
+	}
+	return card, nil
+}
+
+func newFlashcard(topic *studytopic.StudyTopic, dto *FlashcardDTO, now time.Time) *Flashcard {
+	return &Flashcard{
+		ID:        uuid.New(),
+		Front:     dto.Front,
+		Back:      dto.Back,
+		Tags:      dto.normalizedTags(),
+		TopicID:   topic.ID,
+		UserID:    topic.UserID,
+		SRSState:  util.NewSRSState(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (s *flashcardService) CreateFlashcard(ctx context.Context, topicID string, dto *FlashcardDTO) (*Flashcard, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	topic, err := s.getOwnedTopic(ctx, topicID, "create flashcard")
+	if err != nil {
+		return nil, err
+	}
+
+	card := newFlashcard(topic, dto, time.Now())
+	if err := s.repo.Create(card); err != nil {
+		log.WithError(err).Error("Failed to create flashcard")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"flashcard_id": card.ID,
+		"topic_id":     topic.ID,
+	}).Info("Flashcard created successfully")
+	return card, nil
+}
+
+func (s *flashcardService) BulkCreateFlashcards(ctx context.Context, topicID string, dto *BulkFlashcardDTO) ([]*Flashcard, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	topic, err := s.getOwnedTopic(ctx, topicID, "bulk create flashcards")
+	if err != nil {
+		return nil, err
+	}
+
+	// cartões do mesmo lote recebem CreatedAt crescente para manter a ordem enviada
+	now := time.Now()
+	cards := make([]*Flashcard, 0, len(dto.Flashcards))
+	for i := range dto.Flashcards {
+		cards = append(cards, newFlashcard(topic, &dto.Flashcards[i], now.Add(time.Duration(i)*time.Microsecond)))
+	}
+
+	if err := s.repo.CreateBatch(cards); err != nil {
+		log.WithError(err).Error("Failed to bulk create flashcards")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id": topic.ID,
+		"count":    len(cards),
+	}).Info("Flashcards created successfully")
+	return cards, nil
+}
+
+func (s *flashcardService) GetFlashcard(ctx context.Context, id string) (*Flashcard, error) {
+	return s.getOwnedFlashcard(ctx, id, "get flashcard")
+}
+
+func (s *flashcardService) ListFlashcardsByTopic(ctx context.Context, topicID string) ([]*Flashcard, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.getOwnedTopic(ctx, topicID, "list flashcards")
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := s.repo.ListByTopic(topic.ID)
+	if err != nil {
+		log.WithError(err).Error("Error listing flashcards by topic")
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (s *flashcardService) UpdateFlashcard(ctx context.Context, id string, dto *FlashcardDTO) (*Flashcard, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	card, err := s.getOwnedFlashcard(ctx, id, "update flashcard")
+	if err != nil {
+		return nil, err
+	}
+
+	card.Front = dto.Front
+	card.Back = dto.Back
+	card.Tags = dto.normalizedTags()
+	card.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(card); err != nil {
+		log.WithError(err).Error("Failed to update flashcard")
+		return nil, err
+	}
+
+	log.WithField("flashcard_id", card.ID).Info("Flashcard updated successfully")
+	return card, nil
+}
+
+func (s *flashcardService) DeleteFlashcard(ctx context.Context, id string) error {
+	log := config.WithContext(ctx)
+
+	card, err := s.getOwnedFlashcard(ctx, id, "delete flashcard")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(card.ID.String()); err != nil {
+		log.WithError(err).Error("Failed to delete flashcard")
+		return err
+	}
+
+	log.WithField("flashcard_id", card.ID).Info("Flashcard deleted successfully")
+	return nil
+}
+
+// ReviewFlashcard aplica a nota de recordação ao estado SM-2 do cartão.
+func (s *flashcardService) ReviewFlashcard(ctx context.Context, id string, grade int) (*Flashcard, error) {
+	log := config.WithContext(ctx)
+
+	card, err := s.getOwnedFlashcard(ctx, id, "review flashcard")
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := card.SRSState.Review(grade)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	next := state.NextReviewDate(util.LocalNow())
+	card.SRSState = state
+	card.LastGrade = &grade
+	card.LastReviewedAt = &now
+	card.NextReviewAt = &next
+	card.UpdatedAt = now
+
+	if err := s.repo.Update(card); err != nil {
+		log.WithError(err).Error("Failed to save flashcard review")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"flashcard_id":   card.ID,
+		"grade":          grade,
+		"interval_days":  state.IntervalDays,
+		"next_review_at": next,
+	}).Info("Flashcard review recorded successfully")
+	return card, nil
+}
+
+// GetReviewSession monta a sessão: primeiro os cartões vencidos, depois os novos até NewLimit.
+func (s *flashcardService) GetReviewSession(ctx context.Context, subjectID string, opts SessionOptions) ([]*Flashcard, error) {
+	log := config.WithContext(ctx)
+	opts.normalize()
+
+	subject, err := s.getOwnedSubject(ctx, subjectID, "start review session")
+	if err != nil {
+		return nil, err
+	}
+
+	var topicID *uuid.UUID
+	if opts.TopicID != "" {
+		topic, err := s.getOwnedTopic(ctx, opts.TopicID, "start review session")
+		if err != nil {
+			return nil, err
+		}
+		if topic.StudySubjectID != subject.ID {
+			return nil, ErrStudyTopicNotFound
+		}
+		topicID = &topic.ID
+	}
+
+	tomorrow := startOfTomorrow(util.LocalNow())
+	due, err := s.repo.ListDue(subject.ID, topicID, tomorrow, opts.Limit)
+	if err != nil {
+		log.WithError(err).Error("Error listing due flashcards")
+		return nil, err
+	}
+
+	remaining := opts.Limit - len(due)
+	if remaining > opts.NewLimit {
+		remaining = opts.NewLimit
+	}
+	if remaining <= 0 {
+		return due, nil
+	}
+
+	fresh, err := s.repo.ListNew(subject.ID, topicID, remaining)
+	if err != nil {
+		log.WithError(err).Error("Error listing new flashcards")
+		return nil, err
+	}
+	return append(due, fresh...), nil
+}
+
+func (s *flashcardService) GetDeckStats(ctx context.Context, subjectID string) (*DeckStats, error) {
+	log := config.WithContext(ctx)
+
+	subject, err := s.getOwnedSubject(ctx, subjectID, "get deck stats")
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.repo.GetTopicStats(subject.ID, startOfTomorrow(util.LocalNow()))
+	if err != nil {
+		log.WithError(err).Error("Error computing flashcard deck stats")
+		return nil, err
+	}
+
+	stats := &DeckStats{SubjectID: subject.ID, Topics: topics}
+	var easeSum float64
+	var reviewed int64
+	for i := range topics {
+		t := &topics[i]
+		t.AvgEase = math.Round(t.AvgEase*100) / 100
+		stats.Total += t.Total
+		stats.New += t.New
+		stats.Due += t.Due
+		stats.Learning += t.Learning
+		stats.Mature += t.Mature
+		easeSum += t.AvgEase * float64(t.Total-t.New)
+		reviewed += t.Total - t.New
+	}
+	if reviewed > 0 {
+		stats.AvgEase = math.Round(easeSum/float64(reviewed)*100) / 100
+	}
+	if stats.Topics == nil {
+		stats.Topics = []TopicStats{}
+	}
+	return stats, nil
+}
+
+// startOfTomorrow recebe util.LocalNow(): next_review_at é um dia local, e o dia UTC viraria
+// antes da hora, trazendo os cartões de amanhã já à noite.
+func startOfTomorrow(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+}
@@ -14,7 +14,7 @@ import (
 	"google.golang.org/api/option"
 )
 
-const timeZone = "America/Sao_Paulo"
+const timeZone = util.TimeZone
 
 type TaskEventData struct {
 	ID          uuid.UUID
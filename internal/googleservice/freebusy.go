@@ -0,0 +1,72 @@
+package googleservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/task"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+)
+
+type GoogleBusyProvider struct{}
+
+func NewGoogleBusyProvider() *GoogleBusyProvider {
+	return &GoogleBusyProvider{}
+}
+
+// BusySlots consulta o FreeBusy do calendário primário. As datas das tasks são
+// horários locais sem fuso, então a conversão é feita usando o fuso padrão.
+func (p *GoogleBusyProvider) BusySlots(ctx context.Context, accessToken string, from, to time.Time) ([]task.TimeSlot, error) {
+	log := config.WithContext(ctx)
+
+	loc := util.Location()
+
+	token := &oauth2.Token{AccessToken: accessToken, TokenType: "Bearer"}
+	srv, err := NewGoogleCalendarService(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &calendar.FreeBusyRequest{
+		TimeMin:  toZone(from, loc).Format(time.RFC3339),
+		TimeMax:  toZone(to, loc).Format(time.RFC3339),
+		TimeZone: timeZone,
+		Items:    []*calendar.FreeBusyRequestItem{{Id: "primary"}},
+	}
+
+	resp, err := srv.CalendarService.Freebusy.Query(req).Context(ctx).Do()
+	if err != nil {
+		log.WithError(err).Error("Falha ao consultar FreeBusy do Google Calendar")
+		return nil, fmt.Errorf("falha ao consultar FreeBusy: %w", err)
+	}
+
+	var slots []task.TimeSlot
+	for _, cal := range resp.Calendars {
+		for _, period := range cal.Busy {
+			start, errStart := time.Parse(time.RFC3339, period.Start)
+			end, errEnd := time.Parse(time.RFC3339, period.End)
+			if errStart != nil || errEnd != nil {
+				continue
+			}
+			slots = append(slots, task.TimeSlot{
+				Start: fromZone(start.In(loc)),
+				End:   fromZone(end.In(loc)),
+			})
+		}
+	}
+
+	log.WithField("busy_slots", len(slots)).Info("Blocos ocupados obtidos do Google Calendar")
+	return slots, nil
+}
+
+func toZone(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+}
+
+func fromZone(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
@@ -0,0 +1,22 @@
+package milestone
+
+import (
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	"gorm.io/gorm"
+)
+
+type MilestoneContainer struct {
+	Handler *Handler
+	Repo    MilestoneRepository
+}
+
+func NewMilestoneContainer(db *gorm.DB, projectService project.ProjectService) *MilestoneContainer {
+	repo := NewRepository(db)
+	service := NewService(repo, projectService)
+	handler := NewHandler(service)
+
+	return &MilestoneContainer{
+		Handler: handler,
+		Repo:    repo,
+	}
+}
@@ -0,0 +1,24 @@
+package milestone
+
+import (
+	"errors"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+type MilestoneDTO struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	TargetDate  *util.LocalDateTime `json:"target_date"`
+	Status      MilestoneStatus     `json:"status,omitempty"`
+}
+
+func (dto *MilestoneDTO) Validate() error {
+	if dto.Title == "" {
+		return errors.New("title cannot be empty")
+	}
+	if dto.Status != "" && !dto.Status.IsValid() {
+		return errors.New("invalid milestone status")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package milestone
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+type Milestone struct {
+	ID          uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	TargetDate  *util.LocalDateTime `json:"target_date"`
+	Status      MilestoneStatus     `json:"status"`
+	ProjectID   uuid.UUID           `gorm:"column:project_id;not null" json:"project_id"`
+	Project     project.Project     `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID      uuid.UUID           `gorm:"column:user_id;not null" json:"user_id"`
+	User        user.User           `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Completion  *Completion         `gorm:"-" json:"completion,omitempty"`
+}
+
+type Completion struct {
+	TotalTasks  int64   `json:"total_tasks"`
+	DoneTasks   int64   `json:"done_tasks"`
+	PercentDone float64 `json:"percent_done"`
+}
@@ -0,0 +1,24 @@
+package milestone
+
+type MilestoneStatus string
+
+const (
+	PENDING     MilestoneStatus = "PENDING"
+	IN_PROGRESS MilestoneStatus = "IN_PROGRESS"
+	COMPLETED   MilestoneStatus = "COMPLETED"
+)
+
+var AllStatuses = []MilestoneStatus{
+	PENDING,
+	IN_PROGRESS,
+	COMPLETED,
+}
+
+func (s MilestoneStatus) IsValid() bool {
+	for _, v := range AllStatuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package milestone
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+type Handler struct {
+	service MilestoneService
+}
+
+func NewHandler(s MilestoneService) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload MilestoneDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Corpo da requisição inválido")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m, err := h.service.CreateMilestone(r.Context(), chi.URLParam(r, "projectId"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Erro ao criar marco")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, m)
+}
+
+func (h *Handler) GetMilestone(w http.ResponseWriter, r *http.Request) {
+	m, err := h.service.GetMilestone(r.Context(), chi.URLParam(r, "projectId"), chi.URLParam(r, "milestoneId"))
+	if err != nil {
+		writeError(w, r, err, "Erro ao buscar marco")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, m)
+}
+
+func (h *Handler) ListMilestones(w http.ResponseWriter, r *http.Request) {
+	milestones, err := h.service.ListMilestones(r.Context(), chi.URLParam(r, "projectId"))
+	if err != nil {
+		writeError(w, r, err, "Erro ao listar marcos")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":      len(milestones),
+		"milestones": milestones,
+	})
+}
+
+func (h *Handler) UpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload MilestoneDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Corpo da requisição inválido")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := payload.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m, err := h.service.UpdateMilestone(r.Context(), chi.URLParam(r, "projectId"), chi.URLParam(r, "milestoneId"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Erro ao atualizar marco")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, m)
+}
+
+func (h *Handler) DeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteMilestone(r.Context(), chi.URLParam(r, "projectId"), chi.URLParam(r, "milestoneId")); err != nil {
+		writeError(w, r, err, "Erro ao deletar marco")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "milestone deleted successfully",
+	})
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrMilestoneNotFound):
+		http.Error(w, "milestone not found", http.StatusNotFound)
+	case errors.Is(err, ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
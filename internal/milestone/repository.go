@@ -0,0 +1,100 @@
+package milestone
+
+import (
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MilestoneRepository interface {
+	Create(m *Milestone) error
+	GetByID(id string) (*Milestone, error)
+	ListByProject(projectID uuid.UUID) ([]*Milestone, error)
+	Update(m *Milestone) error
+	Delete(id string) error
+	GetCompletion(milestoneIDs []uuid.UUID) (map[uuid.UUID]*Completion, error)
+}
+
+type milestoneRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) MilestoneRepository {
+	return &milestoneRepository{db: db}
+}
+
+func (r *milestoneRepository) Create(m *Milestone) error {
+	return r.db.Create(m).Error
+}
+
+func (r *milestoneRepository) GetByID(id string) (*Milestone, error) {
+	var m Milestone
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *milestoneRepository) ListByProject(projectID uuid.UUID) ([]*Milestone, error) {
+	var milestones []*Milestone
+	if err := r.db.Where("project_id = ?", projectID).Order("target_date ASC NULLS LAST").Find(&milestones).Error; err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+func (r *milestoneRepository) Update(m *Milestone) error {
+	return r.db.Save(m).Error
+}
+
+// Delete remove o marco e desvincula as tasks que apontavam para ele.
+func (r *milestoneRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("tasks").Where("milestone_id = ?", id).Update("milestone_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Milestone{}, "id = ?", id).Error
+	})
+}
+
+type completionRow struct {
+	MilestoneID uuid.UUID
+	TotalTasks  int64
+	DoneTasks   int64
+}
+
+func (r *milestoneRepository) GetCompletion(milestoneIDs []uuid.UUID) (map[uuid.UUID]*Completion, error) {
+	completion := make(map[uuid.UUID]*Completion, len(milestoneIDs))
+	if len(milestoneIDs) == 0 {
+		return completion, nil
+	}
+
+	var rows []completionRow
+	err := r.db.Table("tasks").
+		Select(`milestone_id,
+			COUNT(*) AS total_tasks,
+			COUNT(*) FILTER (WHERE status = 'DONE') AS done_tasks`).
+		Where("milestone_id IN ?", milestoneIDs).
+		Group("milestone_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range milestoneIDs {
+		completion[id] = &Completion{}
+	}
+	for _, row := range rows {
+		c := &Completion{TotalTasks: row.TotalTasks, DoneTasks: row.DoneTasks}
+		if row.TotalTasks > 0 {
+			c.PercentDone = math.Round(float64(row.DoneTasks)/float64(row.TotalTasks)*10000) / 100
+		}
+		completion[row.MilestoneID] = c
+	}
+	return completion, nil
+}
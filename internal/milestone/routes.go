@@ -0,0 +1,20 @@
+package milestone
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Routes é montado dentro do grupo autenticado do router, que já aplica o AuthMiddleware.
+func Routes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Post("/", h.CreateMilestone)
+	r.Get("/", h.ListMilestones)
+	r.Get("/{milestoneId}", h.GetMilestone)
+	r.Put("/{milestoneId}", h.UpdateMilestone)
+	r.Delete("/{milestoneId}", h.DeleteMilestone)
+
+	return r
+}
@@ -0,0 +1,200 @@
+package milestone
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrMilestoneNotFound = errors.New("milestone not found")
+	ErrProjectNotFound   = project.ErrProjectNotFound
+	ErrUnauthorized      = project.ErrUnauthorized
+	ErrForbidden         = project.ErrForbidden
+)
+
+type MilestoneService interface {
+	CreateMilestone(ctx context.Context, projectID string, dto *MilestoneDTO) (*Milestone, error)
+	GetMilestone(ctx context.Context, projectID, id string) (*Milestone, error)
+	ListMilestones(ctx context.Context, projectID string) ([]*Milestone, error)
+	UpdateMilestone(ctx context.Context, projectID, id string, dto *MilestoneDTO) (*Milestone, error)
+	DeleteMilestone(ctx context.Context, projectID, id string) error
+}
+
+type milestoneService struct {
+	repo           MilestoneRepository
+	projectService project.ProjectService
+}
+
+func NewService(repo MilestoneRepository, projectService project.ProjectService) MilestoneService {
+	return &milestoneService{repo: repo, projectService: projectService}
+}
+
+func (s *milestoneService) CreateMilestone(ctx context.Context, projectID string, dto *MilestoneDTO) (*Milestone, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	p, err := s.projectService.AuthorizeProject(ctx, projectID, project.ROLE_EDITOR)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Milestone{
+		ID:          uuid.New(),
+		Title:       dto.Title,
+		Description: dto.Description,
+		TargetDate:  dto.TargetDate,
+		Status:      dto.Status,
+		ProjectID:   p.ID,
+		UserID:      p.UserID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if m.Status == "" {
+		m.Status = PENDING
+	}
+
+	if err := s.repo.Create(m); err != nil {
+		log.WithError(err).Error("Falha ao criar marco")
+		return nil, err
+	}
+
+	m.Completion = &Completion{}
+
+	log.WithFields(logrus.Fields{
+		"milestone_id": m.ID,
+		"project_id":   p.ID,
+	}).Info("Marco criado com sucesso")
+
+	return m, nil
+}
+
+func (s *milestoneService) GetMilestone(ctx context.Context, projectID, id string) (*Milestone, error) {
+	m, err := s.getProjectMilestone(ctx, projectID, id, project.ROLE_VIEWER)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachCompletion(ctx, []*Milestone{m}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *milestoneService) ListMilestones(ctx context.Context, projectID string) ([]*Milestone, error) {
+	log := config.WithContext(ctx)
+
+	p, err := s.projectService.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	milestones, err := s.repo.ListByProject(p.ID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao listar marcos do projeto")
+		return nil, err
+	}
+
+	if err := s.attachCompletion(ctx, milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+func (s *milestoneService) UpdateMilestone(ctx context.Context, projectID, id string, dto *MilestoneDTO) (*Milestone, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	m, err := s.getProjectMilestone(ctx, projectID, id, project.ROLE_EDITOR)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Title = dto.Title
+	m.Description = dto.Description
+	m.TargetDate = dto.TargetDate
+	if dto.Status != "" {
+		m.Status = dto.Status
+	}
+	m.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(m); err != nil {
+		log.WithError(err).Error("Falha ao atualizar marco")
+		return nil, err
+	}
+
+	if err := s.attachCompletion(ctx, []*Milestone{m}); err != nil {
+		return nil, err
+	}
+
+	log.WithField("milestone_id", m.ID).Info("Marco atualizado com sucesso")
+	return m, nil
+}
+
+func (s *milestoneService) DeleteMilestone(ctx context.Context, projectID, id string) error {
+	log := config.WithContext(ctx)
+
+	m, err := s.getProjectMilestone(ctx, projectID, id, project.ROLE_EDITOR)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(m.ID.String()); err != nil {
+		log.WithError(err).Error("Falha ao deletar marco")
+		return err
+	}
+
+	log.WithField("milestone_id", m.ID).Info("Marco deletado com sucesso")
+	return nil
+}
+
+// getProjectMilestone valida o papel do usuário no projeto e que o marco pertence a ele.
+func (s *milestoneService) getProjectMilestone(ctx context.Context, projectID, id string, minRole project.MemberRole) (*Milestone, error) {
+	log := config.WithContext(ctx)
+
+	p, err := s.projectService.AuthorizeProject(ctx, projectID, minRole)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.repo.GetByID(id)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar marco por ID")
+		return nil, err
+	}
+	if m == nil || m.ProjectID != p.ID {
+		return nil, ErrMilestoneNotFound
+	}
+	return m, nil
+}
+
+func (s *milestoneService) attachCompletion(ctx context.Context, milestones []*Milestone) error {
+	log := config.WithContext(ctx)
+
+	ids := make([]uuid.UUID, 0, len(milestones))
+	for _, m := range milestones {
+		ids = append(ids, m.ID)
+	}
+
+	completion, err := s.repo.GetCompletion(ids)
+	if err != nil {
+		log.WithError(err).Error("Erro ao calcular conclusão dos marcos")
+		return err
+	}
+
+	for _, m := range milestones {
+		m.Completion = completion[m.ID]
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package project
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+// ProjectSnapshot guarda a fotografia diária das tasks de um projeto.
+type ProjectSnapshot struct {
+	ProjectID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"project_id"`
+	SnapshotDate             time.Time `gorm:"type:date;primaryKey" json:"snapshot_date"`
+	TotalTasks               int64     `json:"total_tasks"`
+	CompletedTasks           int64     `json:"completed_tasks"`
+	RemainingTasks           int64     `json:"remaining_tasks"`
+	TotalEstimateMinutes     int64     `json:"total_estimate_minutes"`
+	CompletedEstimateMinutes int64     `json:"completed_estimate_minutes"`
+	CreatedAt                time.Time `json:"created_at"`
+}
+
+type BurnPoint struct {
+	Date                     string `json:"date"`
+	TotalTasks               int64  `json:"total_tasks"`
+	CompletedTasks           int64  `json:"completed_tasks"`
+	RemainingTasks           int64  `json:"remaining_tasks"`
+	TotalEstimateMinutes     int64  `json:"total_estimate_minutes"`
+	CompletedEstimateMinutes int64  `json:"completed_estimate_minutes"`
+	RemainingEstimateMinutes int64  `json:"remaining_estimate_minutes"`
+	Source                   string `json:"source"`
+}
+
+type IdealPoint struct {
+	Date                     string  `json:"date"`
+	RemainingTasks           float64 `json:"remaining_tasks"`
+	RemainingEstimateMinutes float64 `json:"remaining_estimate_minutes"`
+}
+
+type BurnChart struct {
+	ProjectID uuid.UUID    `json:"project_id"`
+	Start     string       `json:"start"`
+	End       string       `json:"end"`
+	Deadline  *string      `json:"deadline"`
+	Points    []BurnPoint  `json:"points"`
+	Ideal     []IdealPoint `json:"ideal"`
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// mergeBurnSeries prefere o snapshot do dia e usa a série derivada onde não houver captura.
+func mergeBurnSeries(derived []BurnPoint, snapshots []*ProjectSnapshot) []BurnPoint {
+	byDate := make(map[string]*ProjectSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		byDate[s.SnapshotDate.Format(dateLayout)] = s
+	}
+
+	points := make([]BurnPoint, 0, len(derived))
+	for _, p := range derived {
+		if s, ok := byDate[p.Date]; ok {
+			p = BurnPoint{
+				Date:                     p.Date,
+				TotalTasks:               s.TotalTasks,
+				CompletedTasks:           s.CompletedTasks,
+				RemainingTasks:           s.RemainingTasks,
+				TotalEstimateMinutes:     s.TotalEstimateMinutes,
+				CompletedEstimateMinutes: s.CompletedEstimateMinutes,
+				Source:                   "snapshot",
+			}
+		} else {
+			p.RemainingTasks = p.TotalTasks - p.CompletedTasks
+			p.Source = "derived"
+		}
+		p.RemainingEstimateMinutes = p.TotalEstimateMinutes - p.CompletedEstimateMinutes
+		points = append(points, p)
+	}
+	return points
+}
+
+// idealLine desce linearmente do trabalho total do primeiro dia até zero no prazo.
+func idealLine(start, deadline time.Time, first BurnPoint) []IdealPoint {
+	days := int(deadline.Sub(start).Hours() / 24)
+	if days <= 0 {
+		return []IdealPoint{}
+	}
+
+	ideal := make([]IdealPoint, 0, days+1)
+	for i := 0; i <= days; i++ {
+		remaining := float64(days-i) / float64(days)
+		ideal = append(ideal, IdealPoint{
+			Date:                     start.AddDate(0, 0, i).Format(dateLayout),
+			RemainingTasks:           float64(first.TotalTasks) * remaining,
+			RemainingEstimateMinutes: float64(first.TotalEstimateMinutes) * remaining,
+		})
+	}
+	return ideal
+}
@@ -1,6 +1,9 @@
 package project
 
-import "gorm.io/gorm"
+import (
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"gorm.io/gorm"
+)
 
 type ProjectContainer struct {
 	Handler *Handler
@@ -9,7 +12,9 @@ type ProjectContainer struct {
 
 func NewProjectContainer(db *gorm.DB) *ProjectContainer {
 	repo := NewRepository(db)
-	service := NewService(repo)
+	memberRepo := NewMemberRepository(db)
+	userRepo := user.NewRepository(db)
+	service := NewService(repo, memberRepo, userRepo)
 	handler := NewHandler(service)
 
 	return &ProjectContainer{
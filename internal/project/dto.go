@@ -1,11 +1,19 @@
 package project
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
 
 type UpdateProjectDTO struct {
 	Title       string        `json:"title"`
 	Description string        `json:"description"`
 	Status      ProjectStatus `json:"status,omitempty"`
+	// sem deadline o prazo atual é mantido; clear_deadline remove o prazo
+	Deadline      *time.Time `json:"deadline"`
+	ClearDeadline bool       `json:"clear_deadline"`
 }
 
 func (dto *UpdateProjectDTO) Validate() error {
@@ -15,5 +23,22 @@ func (dto *UpdateProjectDTO) Validate() error {
 	if dto.Status != "" && !dto.Status.IsValid() {
 		return errors.New("invalid project status")
 	}
+	if dto.ClearDeadline && dto.Deadline != nil {
+		return errors.New("deadline and clear_deadline cannot be used together")
+	}
 	return nil
 }
+
+type DeleteOptions struct {
+	Strategy        util.DeleteStrategy
+	TargetProjectID string
+}
+
+type DeletionImpact struct {
+	Tasks      int64 `json:"tasks"`
+	Milestones int64 `json:"milestones"`
+}
+
+func (i *DeletionImpact) HasChildren() bool {
+	return i.Tasks > 0 || i.Milestones > 0
+}
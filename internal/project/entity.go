@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 )
 
 type Project struct {
@@ -12,8 +13,21 @@ type Project struct {
 	Title       string        `json:"title"`
 	Description string        `json:"description"`
 	Status      ProjectStatus `json:"status"`
+	Deadline    *time.Time    `json:"deadline"`
 	UserID      uuid.UUID     `gorm:"column:user_id;not null" json:"user_id"`
 	User        user.User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
+	Progress    *Progress     `gorm:"-" json:"progress,omitempty"`
+}
+
+type Progress struct {
+	TotalTasks    int64               `json:"total_tasks"`
+	TodoTasks     int64               `json:"todo_tasks"`
+	InProgress    int64               `json:"in_progress_tasks"`
+	DoneTasks     int64               `json:"done_tasks"`
+	PercentDone   float64             `json:"percent_done"`
+	OverdueCount  int64               `json:"overdue_count"`
+	NextDueDate   *util.LocalDateTime `json:"next_due_date"`
+	LoggedMinutes int64               `json:"logged_minutes"`
 }
@@ -3,11 +3,13 @@ package project
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 )
 
 type Handler struct {
@@ -57,24 +59,99 @@ func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	project, err := h.service.GetProjectByID(r.Context(), projectID)
+	project, err := h.service.GetProjectWithProgress(r.Context(), projectID)
 	if err != nil {
-		if err == ErrProjectNotFound {
+		switch err {
+		case ErrProjectNotFound:
 			http.Error(w, "project not found", http.StatusNotFound)
-			return
+		case ErrUnauthorized:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrForbidden:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			log.WithError(err).Error("Erro ao buscar projeto")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
 		}
-		log.WithError(err).Error("Erro ao buscar projeto")
-		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	config.JSON(w, http.StatusOK, project)
 }
 
+func (h *Handler) GetProjectProgress(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	projectID := chi.URLParam(r, "id")
+	if projectID == "" {
+		log.Warn("ID do projeto não fornecido")
+		http.Error(w, "project id required", http.StatusBadRequest)
+		return
+	}
+
+	progress, err := h.service.GetProjectProgress(r.Context(), projectID)
+	if err != nil {
+		switch err {
+		case ErrProjectNotFound:
+			http.Error(w, "project not found", http.StatusNotFound)
+		case ErrUnauthorized:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrForbidden:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			log.WithError(err).Error("Erro ao calcular progresso do projeto")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, progress)
+}
+
+func (h *Handler) GetBurnChart(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	projectID := chi.URLParam(r, "id")
+	if projectID == "" {
+		log.Warn("ID do projeto não fornecido")
+		http.Error(w, "project id required", http.StatusBadRequest)
+		return
+	}
+
+	var deadline *time.Time
+	if raw := r.URL.Query().Get("deadline"); raw != "" {
+		parsed, err := time.Parse(dateLayout, raw)
+		if err != nil {
+			http.Error(w, "deadline must use YYYY-MM-DD format", http.StatusBadRequest)
+			return
+		}
+		deadline = &parsed
+	}
+
+	chart, err := h.service.GetBurnChart(r.Context(), projectID, deadline)
+	if err != nil {
+		switch err {
+		case ErrProjectNotFound:
+			http.Error(w, "project not found", http.StatusNotFound)
+		case ErrUnauthorized:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrForbidden:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			log.WithError(err).Error("Erro ao montar burndown do projeto")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, chart)
+}
+
 func (h *Handler) ListProjects(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 
-	projects, err := h.service.ListProjectsByUser(r.Context())
+	includeProgress := r.URL.Query().Get("include") == "progress"
+
+	projects, err := h.service.ListProjectsByUser(r.Context(), includeProgress)
 	if err != nil {
 		log.WithError(err).Error("Erro ao listar projetos")
 		http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -117,6 +194,8 @@ func (h *Handler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "project not found", http.StatusNotFound)
 		case ErrUnauthorized:
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrForbidden:
+			http.Error(w, "forbidden", http.StatusForbidden)
 		default:
 			log.WithError(err).Error("Erro ao atualizar projeto")
 			http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -137,12 +216,29 @@ func (h *Handler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteProject(r.Context(), projectID); err != nil {
+	strategy, err := util.ParseDeleteStrategy(r.URL.Query().Get("strategy"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := DeleteOptions{
+		Strategy:        strategy,
+		TargetProjectID: r.URL.Query().Get("target"),
+	}
+
+	if err := h.service.DeleteProject(r.Context(), projectID, opts); err != nil {
 		switch err {
 		case ErrProjectNotFound:
 			http.Error(w, "project not found", http.StatusNotFound)
 		case ErrUnauthorized:
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrForbidden:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		case ErrInvalidReassignTarget:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case ErrProjectHasChildren:
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			log.WithError(err).Error("Erro ao deletar projeto")
 			http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -154,3 +250,32 @@ func (h *Handler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 		"message": "project deleted successfully",
 	})
 }
+
+func (h *Handler) PreviewDeletion(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	projectID := chi.URLParam(r, "id")
+	if projectID == "" {
+		log.Warn("ID do projeto não fornecido")
+		http.Error(w, "project id required", http.StatusBadRequest)
+		return
+	}
+
+	impact, err := h.service.PreviewDeletion(r.Context(), projectID)
+	if err != nil {
+		switch err {
+		case ErrProjectNotFound:
+			http.Error(w, "project not found", http.StatusNotFound)
+		case ErrUnauthorized:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrForbidden:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			log.WithError(err).Error("Erro ao pré-visualizar exclusão do projeto")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, impact)
+}
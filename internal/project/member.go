@@ -0,0 +1,57 @@
+package project
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MemberRole string
+type MemberStatus string
+
+const (
+	ROLE_OWNER  MemberRole = "OWNER"
+	ROLE_EDITOR MemberRole = "EDITOR"
+	ROLE_VIEWER MemberRole = "VIEWER"
+
+	MEMBER_PENDING  MemberStatus = "PENDING"
+	MEMBER_ACCEPTED MemberStatus = "ACCEPTED"
+)
+
+var roleRank = map[MemberRole]int{
+	ROLE_VIEWER: 1,
+	ROLE_EDITOR: 2,
+	ROLE_OWNER:  3,
+}
+
+func (r MemberRole) IsValid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Allows indica se o papel tem pelo menos as permissões de required.
+func (r MemberRole) Allows(required MemberRole) bool {
+	return roleRank[r] >= roleRank[required]
+}
+
+type ProjectMember struct {
+	ID        uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	ProjectID uuid.UUID    `gorm:"column:project_id;not null" json:"project_id"`
+	Project   *Project     `gorm:"foreignKey:ProjectID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"project,omitempty"`
+	UserID    *uuid.UUID   `gorm:"column:user_id" json:"user_id"`
+	Email     string       `gorm:"not null" json:"email"`
+	Role      MemberRole   `gorm:"not null" json:"role"`
+	Status    MemberStatus `gorm:"not null" json:"status"`
+	InvitedBy uuid.UUID    `gorm:"column:invited_by;not null" json:"invited_by"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type InviteMemberDTO struct {
+	Email string     `json:"email"`
+	Role  MemberRole `json:"role"`
+}
+
+type UpdateMemberDTO struct {
+	Role MemberRole `json:"role"`
+}
@@ -0,0 +1,126 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := h.service.ListMembers(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeMemberError(w, r, err, "Erro ao listar membros do projeto")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":   len(members),
+		"members": members,
+	})
+}
+
+func (h *Handler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload InviteMemberDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Corpo da requisição inválido")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.service.InviteMember(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeMemberError(w, r, err, "Erro ao convidar membro")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, member)
+}
+
+func (h *Handler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload UpdateMemberDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Corpo da requisição inválido")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.service.UpdateMember(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "memberId"), &payload)
+	if err != nil {
+		writeMemberError(w, r, err, "Erro ao atualizar membro")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, member)
+}
+
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveMember(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "memberId")); err != nil {
+		writeMemberError(w, r, err, "Erro ao remover membro")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "member removed successfully",
+	})
+}
+
+func (h *Handler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.service.ListInvitations(r.Context())
+	if err != nil {
+		writeMemberError(w, r, err, "Erro ao listar convites")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":       len(invitations),
+		"invitations": invitations,
+	})
+}
+
+func (h *Handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	member, err := h.service.RespondInvitation(r.Context(), chi.URLParam(r, "memberId"), true)
+	if err != nil {
+		writeMemberError(w, r, err, "Erro ao aceitar convite")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, member)
+}
+
+func (h *Handler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.service.RespondInvitation(r.Context(), chi.URLParam(r, "memberId"), false); err != nil {
+		writeMemberError(w, r, err, "Erro ao recusar convite")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "invitation declined",
+	})
+}
+
+func writeMemberError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrMemberNotFound), errors.Is(err, ErrInvitationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, ErrInvalidMember):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrMemberAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,100 @@
+package project
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MemberRepository interface {
+	Create(m *ProjectMember) error
+	GetByID(id string) (*ProjectMember, error)
+	GetByProjectAndUser(projectID, userID uuid.UUID) (*ProjectMember, error)
+	GetByProjectAndEmail(projectID uuid.UUID, email string) (*ProjectMember, error)
+	ListByProject(projectID uuid.UUID) ([]*ProjectMember, error)
+	ListPendingByEmail(email string) ([]*ProjectMember, error)
+	Update(m *ProjectMember) error
+	Remove(m *ProjectMember) error
+}
+
+type memberRepository struct {
+	db *gorm.DB
+}
+
+func NewMemberRepository(db *gorm.DB) MemberRepository {
+	return &memberRepository{db: db}
+}
+
+func (r *memberRepository) Create(m *ProjectMember) error {
+	return r.db.Create(m).Error
+}
+
+func (r *memberRepository) GetByID(id string) (*ProjectMember, error) {
+	var m ProjectMember
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *memberRepository) GetByProjectAndUser(projectID, userID uuid.UUID) (*ProjectMember, error) {
+	var m ProjectMember
+	err := r.db.Where("project_id = ? AND user_id = ? AND status = ?", projectID, userID, MEMBER_ACCEPTED).First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *memberRepository) GetByProjectAndEmail(projectID uuid.UUID, email string) (*ProjectMember, error) {
+	var m ProjectMember
+	if err := r.db.Where("project_id = ? AND LOWER(email) = LOWER(?)", projectID, email).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *memberRepository) ListByProject(projectID uuid.UUID) ([]*ProjectMember, error) {
+	var members []*ProjectMember
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at ASC").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *memberRepository) ListPendingByEmail(email string) ([]*ProjectMember, error) {
+	var members []*ProjectMember
+	err := r.db.Preload("Project").
+		Where("LOWER(email) = LOWER(?) AND status = ?", email, MEMBER_PENDING).
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *memberRepository) Update(m *ProjectMember) error {
+	return r.db.Save(m).Error
+}
+
+// Remove exclui o membro e desfaz as atribuições de tasks dele no projeto.
+func (r *memberRepository) Remove(m *ProjectMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if m.UserID != nil {
+			if err := tx.Exec("UPDATE tasks SET assignee_id = NULL WHERE project_id = ? AND assignee_id = ?", m.ProjectID, *m.UserID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&ProjectMember{}, "id = ?", m.ID).Error
+	})
+}
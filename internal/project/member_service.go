@@ -0,0 +1,286 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrMemberNotFound      = errors.New("member not found")
+	ErrMemberAlreadyExists = errors.New("user is already a member or invited")
+	ErrInvalidMember       = errors.New("invalid member data")
+	ErrInvitationNotFound  = errors.New("invitation not found")
+)
+
+func (s *projectService) ListMembers(ctx context.Context, projectID string) ([]*ProjectMember, error) {
+	log := config.WithContext(ctx)
+
+	project, err := s.AuthorizeProject(ctx, projectID, ROLE_VIEWER)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.ListByProject(project.ID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao listar membros do projeto")
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(project.UserID.String())
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar dono do projeto")
+		return nil, err
+	}
+
+	// o dono é implícito (Project.UserID) e não possui linha em project_members
+	ownerMember := &ProjectMember{
+		ProjectID: project.ID,
+		UserID:    &project.UserID,
+		Role:      ROLE_OWNER,
+		Status:    MEMBER_ACCEPTED,
+		InvitedBy: project.UserID,
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+	if owner != nil {
+		ownerMember.Email = owner.Email
+	}
+
+	return append([]*ProjectMember{ownerMember}, members...), nil
+}
+
+func (s *projectService) InviteMember(ctx context.Context, projectID string, dto *InviteMemberDTO) (*ProjectMember, error) {
+	log := config.WithContext(ctx)
+
+	email := strings.TrimSpace(strings.ToLower(dto.Email))
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, ErrInvalidMember
+	}
+	if dto.Role != ROLE_EDITOR && dto.Role != ROLE_VIEWER {
+		return nil, ErrInvalidMember
+	}
+
+	project, err := s.AuthorizeProject(ctx, projectID, ROLE_OWNER)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(project.UserID.String())
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar dono do projeto")
+		return nil, err
+	}
+	if owner != nil && strings.EqualFold(owner.Email, email) {
+		return nil, ErrMemberAlreadyExists
+	}
+
+	existing, err := s.memberRepo.GetByProjectAndEmail(project.ID, email)
+	if err != nil {
+		log.WithError(err).Error("Erro ao verificar convite existente")
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrMemberAlreadyExists
+	}
+
+	member := &ProjectMember{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Email:     email,
+		Role:      dto.Role,
+		Status:    MEMBER_PENDING,
+		InvitedBy: project.UserID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.memberRepo.Create(member); err != nil {
+		log.WithError(err).Error("Falha ao criar convite de membro")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"project_id": project.ID,
+		"member_id":  member.ID,
+		"role":       member.Role,
+	}).Info("Convite de membro criado com sucesso")
+
+	return member, nil
+}
+
+func (s *projectService) UpdateMember(ctx context.Context, projectID, memberID string, dto *UpdateMemberDTO) (*ProjectMember, error) {
+	log := config.WithContext(ctx)
+
+	if dto.Role != ROLE_EDITOR && dto.Role != ROLE_VIEWER {
+		return nil, ErrInvalidMember
+	}
+
+	project, err := s.AuthorizeProject(ctx, projectID, ROLE_OWNER)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.getProjectMember(log, project.ID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Role = dto.Role
+	member.UpdatedAt = time.Now()
+
+	if err := s.memberRepo.Update(member); err != nil {
+		log.WithError(err).Error("Falha ao atualizar membro do projeto")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"project_id": project.ID,
+		"member_id":  member.ID,
+		"role":       member.Role,
+	}).Info("Papel do membro atualizado com sucesso")
+
+	return member, nil
+}
+
+// RemoveMember permite ao dono remover qualquer membro e a cada membro sair do projeto.
+func (s *projectService) RemoveMember(ctx context.Context, projectID, memberID string) error {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Tentativa de remover membro sem autenticação")
+		return ErrUnauthorized
+	}
+
+	project, err := s.AuthorizeProject(ctx, projectID, ROLE_VIEWER)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.getProjectMember(log, project.ID, memberID)
+	if err != nil {
+		return err
+	}
+
+	isSelf := member.UserID != nil && auth.IsOwner(claims.UserID, *member.UserID)
+	if !auth.IsOwner(claims.UserID, project.UserID) && !isSelf {
+		log.WithFields(logrus.Fields{
+			"project_id": project.ID,
+			"member_id":  member.ID,
+			"user_id":    claims.UserID,
+		}).Warn("Usuário tentou remover outro membro sem ser dono do projeto")
+		return ErrForbidden
+	}
+
+	if err := s.memberRepo.Remove(member); err != nil {
+		log.WithError(err).Error("Falha ao remover membro do projeto")
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		"project_id": project.ID,
+		"member_id":  member.ID,
+	}).Info("Membro removido do projeto com sucesso")
+
+	return nil
+}
+
+func (s *projectService) ListInvitations(ctx context.Context) ([]*ProjectMember, error) {
+	log := config.WithContext(ctx)
+
+	u, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.memberRepo.ListPendingByEmail(u.Email)
+	if err != nil {
+		log.WithError(err).Error("Erro ao listar convites do usuário")
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (s *projectService) RespondInvitation(ctx context.Context, memberID string, accept bool) (*ProjectMember, error) {
+	log := config.WithContext(ctx)
+
+	u, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar convite")
+		return nil, err
+	}
+	if member == nil || member.Status != MEMBER_PENDING || !strings.EqualFold(member.Email, u.Email) {
+		return nil, ErrInvitationNotFound
+	}
+
+	if !accept {
+		if err := s.memberRepo.Remove(member); err != nil {
+			log.WithError(err).Error("Falha ao recusar convite")
+			return nil, err
+		}
+		log.WithField("member_id", member.ID).Info("Convite recusado")
+		return nil, nil
+	}
+
+	member.UserID = &u.ID
+	member.Status = MEMBER_ACCEPTED
+	member.UpdatedAt = time.Now()
+
+	if err := s.memberRepo.Update(member); err != nil {
+		log.WithError(err).Error("Falha ao aceitar convite")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"project_id": member.ProjectID,
+		"member_id":  member.ID,
+		"user_id":    u.ID,
+	}).Info("Convite aceito com sucesso")
+
+	return member, nil
+}
+
+func (s *projectService) getProjectMember(log logrus.FieldLogger, projectID uuid.UUID, memberID string) (*ProjectMember, error) {
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar membro do projeto")
+		return nil, err
+	}
+	if member == nil || member.ProjectID != projectID {
+		return nil, ErrMemberNotFound
+	}
+	return member, nil
+}
+
+func (s *projectService) currentUser(ctx context.Context) (*user.User, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Tentativa de acessar convites sem autenticação")
+		return nil, ErrUnauthorized
+	}
+
+	u, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar usuário autenticado")
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUnauthorized
+	}
+	return u, nil
+}
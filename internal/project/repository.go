@@ -2,8 +2,11 @@ package project
 
 import (
 	"errors"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 	"gorm.io/gorm"
 )
 
@@ -13,6 +16,13 @@ type ProjectRepository interface {
 	ListByUser(userID uuid.UUID) ([]*Project, error)
 	Update(p *Project) error
 	Delete(id string) error
+	CountChildren(id uuid.UUID) (*DeletionImpact, error)
+	DeleteWithStrategy(id uuid.UUID, strategy util.DeleteStrategy, targetID uuid.UUID) error
+	GetProgress(projectIDs []uuid.UUID, now time.Time) (map[uuid.UUID]*Progress, error)
+	CaptureSnapshots(day time.Time) (int64, error)
+	ListSnapshots(projectID uuid.UUID, from, to time.Time) ([]*ProjectSnapshot, error)
+	DeriveBurnSeries(projectID uuid.UUID, from, to time.Time) ([]BurnPoint, error)
+	LatestTaskDueDate(projectID uuid.UUID) (*time.Time, error)
 }
 
 type projectRepository struct {
@@ -40,7 +50,8 @@ func (r *projectRepository) GetByID(id string) (*Project, error) {
 
 func (r *projectRepository) ListByUser(userID uuid.UUID) ([]*Project, error) {
 	var projects []*Project
-	if err := r.db.Where("user_id = ?", userID).Find(&projects).Error; err != nil {
+	memberOf := r.db.Table("project_members").Select("project_id").Where("user_id = ? AND status = ?", userID, MEMBER_ACCEPTED)
+	if err := r.db.Where("user_id = ? OR id IN (?)", userID, memberOf).Find(&projects).Error; err != nil {
 		return nil, err
 	}
 	return projects, nil
@@ -53,3 +64,233 @@ func (r *projectRepository) Update(p *Project) error {
 func (r *projectRepository) Delete(id string) error {
 	return r.db.Delete(&Project{}, "id = ?", id).Error
 }
+
+func (r *projectRepository) CountChildren(id uuid.UUID) (*DeletionImpact, error) {
+	return countChildren(r.db, id)
+}
+
+func countChildren(db *gorm.DB, id uuid.UUID) (*DeletionImpact, error) {
+	var impact DeletionImpact
+	if err := db.Table("tasks").Where("project_id = ?", id).Count(&impact.Tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("milestones").Where("project_id = ?", id).Count(&impact.Milestones).Error; err != nil {
+		return nil, err
+	}
+	return &impact, nil
+}
+
+// DeleteWithStrategy remove o projeto tratando tasks e marcos numa única transação.
+func (r *projectRepository) DeleteWithStrategy(id uuid.UUID, strategy util.DeleteStrategy, targetID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		switch strategy {
+		case util.DeleteCascade:
+			if err := deleteTaskDependencies(tx, id); err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM tasks WHERE project_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM milestones WHERE project_id = ?", id).Error; err != nil {
+				return err
+			}
+		case util.DeleteReassign:
+			// o responsável só continua se também tiver acesso ao projeto de destino; senão a
+			// task ficaria com alguém que não a enxerga e falharia na próxima edição
+			if err := tx.Exec(`UPDATE tasks SET project_id = ?,
+				assignee_id = CASE
+					WHEN assignee_id = (SELECT user_id FROM projects WHERE id = ?) THEN assignee_id
+					WHEN EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = ?
+						AND pm.user_id = tasks.assignee_id AND pm.status = ?) THEN assignee_id
+					ELSE NULL END
+				WHERE project_id = ?`, targetID, targetID, targetID, MEMBER_ACCEPTED, id).Error; err != nil {
+				return err
+			}
+			// o marco passa a ser do dono do projeto de destino
+			if err := tx.Exec(`UPDATE milestones SET project_id = ?, user_id = (SELECT user_id FROM projects WHERE id = ?)
+				WHERE project_id = ?`, targetID, targetID, id).Error; err != nil {
+				return err
+			}
+		case util.DeleteDetach:
+			if err := deleteTaskDependencies(tx, id); err != nil {
+				return err
+			}
+			// task PROJECT sem projeto não passa na validação, e responsável só existe dentro de
+			// um projeto; sem os dois ajustes as tasks soltas ficariam impossíveis de editar
+			if err := tx.Exec(`UPDATE tasks SET project_id = NULL, milestone_id = NULL, assignee_id = NULL,
+				type = CASE WHEN type = 'PROJECT' THEN 'EVENT' ELSE type END
+				WHERE project_id = ?`, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM milestones WHERE project_id = ?", id).Error; err != nil {
+				return err
+			}
+		default:
+			impact, err := countChildren(tx, id)
+			if err != nil {
+				return err
+			}
+			if impact.HasChildren() {
+				return ErrProjectHasChildren
+			}
+		}
+
+		// membros, convites e snapshots pertencem só ao projeto, qualquer que seja a estratégia
+		if err := tx.Exec("DELETE FROM project_members WHERE project_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM project_snapshots WHERE project_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&Project{}, "id = ?", id).Error
+	})
+}
+
+// deleteTaskDependencies remove as dependências entre tasks do projeto, que só existem dentro dele.
+func deleteTaskDependencies(tx *gorm.DB, projectID uuid.UUID) error {
+	return tx.Exec(`DELETE FROM task_dependencies
+		WHERE task_id IN (SELECT id FROM tasks WHERE project_id = ?)
+		OR depends_on_id IN (SELECT id FROM tasks WHERE project_id = ?)`, projectID, projectID).Error
+}
+
+type progressRow struct {
+	ProjectID     uuid.UUID
+	TotalTasks    int64
+	TodoTasks     int64
+	InProgress    int64
+	DoneTasks     int64
+	OverdueCount  int64
+	NextDueDate   *util.LocalDateTime
+	LoggedMinutes int64
+}
+
+// GetProgress agrega as tasks de cada projeto em uma única consulta, sem carregar as tasks.
+// now deve estar no mesmo formato de due_date, que é horário local sem fuso.
+func (r *projectRepository) GetProgress(projectIDs []uuid.UUID, now time.Time) (map[uuid.UUID]*Progress, error) {
+	progress := make(map[uuid.UUID]*Progress, len(projectIDs))
+	if len(projectIDs) == 0 {
+		return progress, nil
+	}
+
+	var rows []progressRow
+	err := r.db.Table("tasks").
+		Select(`project_id,
+			COUNT(*) AS total_tasks,
+			COUNT(*) FILTER (WHERE status = 'TODO') AS todo_tasks,
+			COUNT(*) FILTER (WHERE status = 'IN_PROGRESS') AS in_progress,
+			COUNT(*) FILTER (WHERE status = 'DONE') AS done_tasks,
+			COUNT(*) FILTER (WHERE status <> 'DONE' AND due_date < ?) AS overdue_count,
+			MIN(due_date) FILTER (WHERE status <> 'DONE' AND due_date >= ?) AS next_due_date,
+			COALESCE(SUM(logged_minutes), 0) AS logged_minutes`, now, now).
+		Where("project_id IN ?", projectIDs).
+		Group("project_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range projectIDs {
+		progress[id] = &Progress{}
+	}
+	for _, row := range rows {
+		p := &Progress{
+			TotalTasks:    row.TotalTasks,
+			TodoTasks:     row.TodoTasks,
+			InProgress:    row.InProgress,
+			DoneTasks:     row.DoneTasks,
+			OverdueCount:  row.OverdueCount,
+			NextDueDate:   row.NextDueDate,
+			LoggedMinutes: row.LoggedMinutes,
+		}
+		if row.TotalTasks > 0 {
+			p.PercentDone = math.Round(float64(row.DoneTasks)/float64(row.TotalTasks)*10000) / 100
+		}
+		progress[row.ProjectID] = p
+	}
+	return progress, nil
+}
+
+// CaptureSnapshots grava (ou sobrescreve) o snapshot do dia para todos os projetos não concluídos.
+func (r *projectRepository) CaptureSnapshots(day time.Time) (int64, error) {
+	res := r.db.Exec(`INSERT INTO project_snapshots
+			(project_id, snapshot_date, total_tasks, completed_tasks, remaining_tasks,
+			total_estimate_minutes, completed_estimate_minutes, created_at)
+		SELECT p.id, ?::date,
+			COUNT(t.id),
+			COUNT(t.id) FILTER (WHERE t.status = 'DONE'),
+			COUNT(t.id) FILTER (WHERE t.status <> 'DONE'),
+			COALESCE(SUM(t.estimated_minutes), 0),
+			COALESCE(SUM(t.estimated_minutes) FILTER (WHERE t.status = 'DONE'), 0),
+			NOW()
+		FROM projects p
+		LEFT JOIN tasks t ON t.project_id = p.id
+		WHERE p.status <> 'COMPLETED'
+		GROUP BY p.id
+		ON CONFLICT (project_id, snapshot_date) DO UPDATE SET
+			total_tasks = EXCLUDED.total_tasks,
+			completed_tasks = EXCLUDED.completed_tasks,
+			remaining_tasks = EXCLUDED.remaining_tasks,
+			total_estimate_minutes = EXCLUDED.total_estimate_minutes,
+			completed_estimate_minutes = EXCLUDED.completed_estimate_minutes,
+			created_at = EXCLUDED.created_at`, day.Format(dateLayout))
+	return res.RowsAffected, res.Error
+}
+
+func (r *projectRepository) ListSnapshots(projectID uuid.UUID, from, to time.Time) ([]*ProjectSnapshot, error) {
+	var snapshots []*ProjectSnapshot
+	err := r.db.Where("project_id = ? AND snapshot_date BETWEEN ? AND ?", projectID, from.Format(dateLayout), to.Format(dateLayout)).
+		Order("snapshot_date").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+type burnRow struct {
+	Day                      time.Time
+	TotalTasks               int64
+	CompletedTasks           int64
+	TotalEstimateMinutes     int64
+	CompletedEstimateMinutes int64
+}
+
+// DeriveBurnSeries reconstrói a série diária a partir de created_at e done_at das tasks atuais.
+// Tasks concluídas sem done_at usam updated_at como momento de conclusão.
+func (r *projectRepository) DeriveBurnSeries(projectID uuid.UUID, from, to time.Time) ([]BurnPoint, error) {
+	const doneAt = "CASE WHEN t.done_at > t.created_at THEN t.done_at ELSE t.updated_at END"
+
+	var rows []burnRow
+	err := r.db.Raw(`SELECT d::date AS day,
+			COUNT(t.id) FILTER (WHERE t.created_at < d + INTERVAL '1 day') AS total_tasks,
+			COUNT(t.id) FILTER (WHERE t.status = 'DONE' AND `+doneAt+` < d + INTERVAL '1 day') AS completed_tasks,
+			COALESCE(SUM(t.estimated_minutes) FILTER (WHERE t.created_at < d + INTERVAL '1 day'), 0) AS total_estimate_minutes,
+			COALESCE(SUM(t.estimated_minutes) FILTER (WHERE t.status = 'DONE' AND `+doneAt+` < d + INTERVAL '1 day'), 0) AS completed_estimate_minutes
+		FROM generate_series(?::date, ?::date, INTERVAL '1 day') AS d
+		LEFT JOIN tasks t ON t.project_id = ?
+		GROUP BY d
+		ORDER BY d`, from.Format(dateLayout), to.Format(dateLayout), projectID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]BurnPoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, BurnPoint{
+			Date:                     row.Day.Format(dateLayout),
+			TotalTasks:               row.TotalTasks,
+			CompletedTasks:           row.CompletedTasks,
+			TotalEstimateMinutes:     row.TotalEstimateMinutes,
+			CompletedEstimateMinutes: row.CompletedEstimateMinutes,
+		})
+	}
+	return points, nil
+}
+
+func (r *projectRepository) LatestTaskDueDate(projectID uuid.UUID) (*time.Time, error) {
+	var due *time.Time
+	err := r.db.Table("tasks").
+		Select("MAX(due_date)").
+		Where("project_id = ?", projectID).
+		Scan(&due).Error
+	return due, err
+}
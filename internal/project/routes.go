@@ -14,9 +14,20 @@ func Routes(h *Handler) http.Handler {
 
 	r.Post("/", h.CreateProject)
 	r.Get("/", h.ListProjects)
+	r.Get("/invitations", h.ListInvitations)
+	r.Post("/invitations/{memberId}/accept", h.AcceptInvitation)
+	r.Post("/invitations/{memberId}/decline", h.DeclineInvitation)
 	r.Get("/{id}", h.GetProject)
+	r.Get("/{id}/progress", h.GetProjectProgress)
+	r.Get("/{id}/burndown", h.GetBurnChart)
 	r.Put("/{id}", h.UpdateProject)
+	r.Get("/{id}/deletion-preview", h.PreviewDeletion)
 	r.Delete("/{id}", h.DeleteProject)
 
+	r.Get("/{id}/members", h.ListMembers)
+	r.Post("/{id}/members", h.InviteMember)
+	r.Put("/{id}/members/{memberId}", h.UpdateMember)
+	r.Delete("/{id}/members/{memberId}", h.RemoveMember)
+
 	return r
 }
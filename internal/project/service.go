@@ -8,28 +8,50 @@ import (
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 	"github.com/sirupsen/logrus"
 )
 
 var (
 	ErrProjectNotFound = errors.New("project not found")
 	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("insufficient project permissions")
+
+	ErrProjectHasChildren    = errors.New("project has tasks or milestones")
+	ErrInvalidReassignTarget = errors.New("invalid reassign target project")
 )
 
 type ProjectService interface {
 	CreateProject(ctx context.Context, p *Project) (*Project, error)
 	GetProjectByID(ctx context.Context, id string) (*Project, error)
-	ListProjectsByUser(ctx context.Context) ([]*Project, error)
+	AuthorizeProject(ctx context.Context, id string, minRole MemberRole) (*Project, error)
+	HasMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error)
+	GetProjectWithProgress(ctx context.Context, id string) (*Project, error)
+	GetProjectProgress(ctx context.Context, id string) (*Progress, error)
+	ListProjectsByUser(ctx context.Context, includeProgress bool) ([]*Project, error)
 	UpdateProject(ctx context.Context, id string, dto *UpdateProjectDTO) (*Project, error)
-	DeleteProject(ctx context.Context, id string) error
+	DeleteProject(ctx context.Context, id string, opts DeleteOptions) error
+	PreviewDeletion(ctx context.Context, id string) (*DeletionImpact, error)
+	GetBurnChart(ctx context.Context, id string, deadline *time.Time) (*BurnChart, error)
+	CaptureDailySnapshots(ctx context.Context) (int64, error)
+
+	ListMembers(ctx context.Context, projectID string) ([]*ProjectMember, error)
+	InviteMember(ctx context.Context, projectID string, dto *InviteMemberDTO) (*ProjectMember, error)
+	UpdateMember(ctx context.Context, projectID, memberID string, dto *UpdateMemberDTO) (*ProjectMember, error)
+	RemoveMember(ctx context.Context, projectID, memberID string) error
+	ListInvitations(ctx context.Context) ([]*ProjectMember, error)
+	RespondInvitation(ctx context.Context, memberID string, accept bool) (*ProjectMember, error)
 }
 
 type projectService struct {
-	repo ProjectRepository
+	repo       ProjectRepository
+	memberRepo MemberRepository
+	userRepo   user.UserRepository
 }
 
-func NewService(repo ProjectRepository) ProjectService {
-	return &projectService{repo: repo}
+func NewService(repo ProjectRepository, memberRepo MemberRepository, userRepo user.UserRepository) ProjectService {
+	return &projectService{repo: repo, memberRepo: memberRepo, userRepo: userRepo}
 }
 
 func (s *projectService) CreateProject(ctx context.Context, p *Project) (*Project, error) {
@@ -69,6 +91,11 @@ func (s *projectService) CreateProject(ctx context.Context, p *Project) (*Projec
 }
 
 func (s *projectService) GetProjectByID(ctx context.Context, id string) (*Project, error) {
+	return s.AuthorizeProject(ctx, id, ROLE_VIEWER)
+}
+
+// AuthorizeProject busca o projeto e exige que o usuário autenticado tenha pelo menos minRole nele.
+func (s *projectService) AuthorizeProject(ctx context.Context, id string, minRole MemberRole) (*Project, error) {
 	log := config.WithContext(ctx)
 
 	claims, err := auth.GetUserClaimsFromContext(ctx)
@@ -86,18 +113,110 @@ func (s *projectService) GetProjectByID(ctx context.Context, id string) (*Projec
 		return nil, ErrProjectNotFound
 	}
 
-	if project.UserID.String() != claims.UserID {
+	role, err := s.roleFor(project, claims.UserID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar papel do usuário no projeto")
+		return nil, err
+	}
+
+	if role == "" {
 		log.WithFields(logrus.Fields{
 			"project_id": project.ID,
 			"user_id":    claims.UserID,
-		}).Warn("Usuário tentou acessar projeto de outro usuário")
+		}).Warn("Usuário tentou acessar projeto do qual não é membro")
 		return nil, ErrUnauthorized
 	}
 
+	if !role.Allows(minRole) {
+		log.WithFields(logrus.Fields{
+			"project_id":    project.ID,
+			"user_id":       claims.UserID,
+			"role":          role,
+			"required_role": minRole,
+		}).Warn("Usuário sem permissão suficiente no projeto")
+		return nil, ErrForbidden
+	}
+
+	return project, nil
+}
+
+func (s *projectService) roleFor(project *Project, userID string) (MemberRole, error) {
+	if auth.IsOwner(userID, project.UserID) {
+		return ROLE_OWNER, nil
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return "", nil
+	}
+
+	member, err := s.memberRepo.GetByProjectAndUser(project.ID, uid)
+	if err != nil {
+		return "", err
+	}
+	if member == nil {
+		return "", nil
+	}
+	return member.Role, nil
+}
+
+func (s *projectService) HasMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
+	project, err := s.repo.GetByID(projectID.String())
+	if err != nil {
+		return false, err
+	}
+	if project == nil {
+		return false, ErrProjectNotFound
+	}
+
+	role, err := s.roleFor(project, userID.String())
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
+
+func (s *projectService) GetProjectWithProgress(ctx context.Context, id string) (*Project, error) {
+	project, err := s.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachProgress(ctx, []*Project{project}); err != nil {
+		return nil, err
+	}
 	return project, nil
 }
 
-func (s *projectService) ListProjectsByUser(ctx context.Context) ([]*Project, error) {
+func (s *projectService) GetProjectProgress(ctx context.Context, id string) (*Progress, error) {
+	project, err := s.GetProjectWithProgress(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return project.Progress, nil
+}
+
+func (s *projectService) attachProgress(ctx context.Context, projects []*Project) error {
+	log := config.WithContext(ctx)
+
+	ids := make([]uuid.UUID, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+
+	progress, err := s.repo.GetProgress(ids, util.LocalNow())
+	if err != nil {
+		log.WithError(err).Error("Erro ao calcular progresso dos projetos")
+		return err
+	}
+
+	for _, p := range projects {
+		p.Progress = progress[p.ID]
+	}
+	return nil
+}
+
+func (s *projectService) ListProjectsByUser(ctx context.Context, includeProgress bool) ([]*Project, error) {
 	log := config.WithContext(ctx)
 
 	claims, err := auth.GetUserClaimsFromContext(ctx)
@@ -113,6 +232,12 @@ func (s *projectService) ListProjectsByUser(ctx context.Context) ([]*Project, er
 		return nil, err
 	}
 
+	if includeProgress {
+		if err := s.attachProgress(ctx, projects); err != nil {
+			return nil, err
+		}
+	}
+
 	log.WithFields(logrus.Fields{
 		"user_id": claims.UserID,
 		"count":   len(projects),
@@ -124,35 +249,23 @@ func (s *projectService) ListProjectsByUser(ctx context.Context) ([]*Project, er
 func (s *projectService) UpdateProject(ctx context.Context, id string, dto *UpdateProjectDTO) (*Project, error) {
 	log := config.WithContext(ctx)
 
-	claims, err := auth.GetUserClaimsFromContext(ctx)
-	if err != nil {
-		log.WithError(err).Warn("Tentativa de atualizar projeto sem autenticação")
-		return nil, ErrUnauthorized
-	}
-
 	if err := dto.Validate(); err != nil {
 		return nil, err
 	}
 
-	existing, err := s.repo.GetByID(id)
+	existing, err := s.AuthorizeProject(ctx, id, ROLE_EDITOR)
 	if err != nil {
-		log.WithError(err).Error("Erro ao buscar projeto para atualização")
 		return nil, err
 	}
-	if existing == nil {
-		return nil, ErrProjectNotFound
-	}
-
-	if existing.UserID.String() != claims.UserID {
-		log.WithFields(logrus.Fields{
-			"project_id": existing.ID,
-			"user_id":    claims.UserID,
-		}).Warn("Usuário tentou atualizar projeto de outro usuário")
-		return nil, ErrUnauthorized
-	}
 
 	existing.Title = dto.Title
 	existing.Description = dto.Description
+	switch {
+	case dto.ClearDeadline:
+		existing.Deadline = nil
+	case dto.Deadline != nil:
+		existing.Deadline = dto.Deadline
+	}
 
 	existing.UpdatedAt = time.Now()
 
@@ -161,49 +274,138 @@ func (s *projectService) UpdateProject(ctx context.Context, id string, dto *Upda
 		return nil, err
 	}
 
-	log.WithFields(logrus.Fields{
-		"project_id": existing.ID,
-		"user_id":    claims.UserID,
-	}).Info("Projeto atualizado com sucesso")
+	log.WithField("project_id", existing.ID).Info("Projeto atualizado com sucesso")
 
 	return existing, nil
 }
 
-func (s *projectService) DeleteProject(ctx context.Context, id string) error {
+func (s *projectService) DeleteProject(ctx context.Context, id string, opts DeleteOptions) error {
 	log := config.WithContext(ctx)
 
-	claims, err := auth.GetUserClaimsFromContext(ctx)
-	if err != nil {
-		log.WithError(err).Warn("Tentativa de deletar projeto sem autenticação")
-		return ErrUnauthorized
-	}
-
-	project, err := s.repo.GetByID(id)
+	project, err := s.AuthorizeProject(ctx, id, ROLE_OWNER)
 	if err != nil {
-		log.WithError(err).Error("Erro ao buscar projeto para exclusão")
 		return err
 	}
-	if project == nil {
-		return ErrProjectNotFound
-	}
 
-	if project.UserID.String() != claims.UserID {
-		log.WithFields(logrus.Fields{
-			"project_id": project.ID,
-			"user_id":    claims.UserID,
-		}).Warn("Usuário tentou deletar projeto de outro usuário")
-		return ErrUnauthorized
+	var targetID uuid.UUID
+	if opts.Strategy == util.DeleteReassign {
+		if opts.TargetProjectID == "" || opts.TargetProjectID == id {
+			return ErrInvalidReassignTarget
+		}
+		target, err := s.AuthorizeProject(ctx, opts.TargetProjectID, ROLE_EDITOR)
+		if err != nil {
+			if errors.Is(err, ErrProjectNotFound) {
+				return ErrInvalidReassignTarget
+			}
+			return err
+		}
+		targetID = target.ID
 	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.DeleteWithStrategy(project.ID, opts.Strategy, targetID); err != nil {
+		if errors.Is(err, ErrProjectHasChildren) {
+			log.WithField("project_id", id).Warn("Exclusão de projeto bloqueada por possuir filhos")
+			return err
+		}
 		log.WithError(err).Error("Falha ao deletar projeto")
 		return err
 	}
 
 	log.WithFields(logrus.Fields{
 		"project_id": id,
-		"user_id":    claims.UserID,
+		"user_id":    project.UserID,
+		"strategy":   opts.Strategy,
 	}).Info("Projeto deletado com sucesso")
 
 	return nil
 }
+
+func (s *projectService) PreviewDeletion(ctx context.Context, id string) (*DeletionImpact, error) {
+	log := config.WithContext(ctx)
+
+	project, err := s.AuthorizeProject(ctx, id, ROLE_OWNER)
+	if err != nil {
+		return nil, err
+	}
+
+	impact, err := s.repo.CountChildren(project.ID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao contar filhos do projeto")
+		return nil, err
+	}
+	return impact, nil
+}
+
+// GetBurnChart monta as séries de burndown/burnup desde a criação do projeto até hoje,
+// com a linha ideal até o prazo (parâmetro, prazo do projeto ou maior due date das tasks).
+func (s *projectService) GetBurnChart(ctx context.Context, id string, deadline *time.Time) (*BurnChart, error) {
+	log := config.WithContext(ctx)
+
+	project, err := s.GetProjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline == nil {
+		deadline = project.Deadline
+	}
+	if deadline == nil {
+		deadline, err = s.repo.LatestTaskDueDate(project.ID)
+		if err != nil {
+			log.WithError(err).Error("Erro ao buscar prazo das tasks do projeto")
+			return nil, err
+		}
+	}
+
+	start := truncateDay(project.CreatedAt)
+	end := truncateDay(util.LocalNow())
+	if end.Before(start) {
+		end = start
+	}
+
+	derived, err := s.repo.DeriveBurnSeries(project.ID, start, end)
+	if err != nil {
+		log.WithError(err).Error("Erro ao derivar série de burndown do projeto")
+		return nil, err
+	}
+	snapshots, err := s.repo.ListSnapshots(project.ID, start, end)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar snapshots do projeto")
+		return nil, err
+	}
+
+	chart := &BurnChart{
+		ProjectID: project.ID,
+		Start:     start.Format(dateLayout),
+		End:       end.Format(dateLayout),
+		Points:    mergeBurnSeries(derived, snapshots),
+		Ideal:     []IdealPoint{},
+	}
+	if deadline != nil && len(chart.Points) > 0 {
+		day := truncateDay(*deadline)
+		formatted := day.Format(dateLayout)
+		chart.Deadline = &formatted
+		chart.Ideal = idealLine(start, day, chart.Points[0])
+	}
+
+	return chart, nil
+}
+
+// CaptureDailySnapshots é executado pelo job agendado, fora do contexto de um usuário.
+func (s *projectService) CaptureDailySnapshots(ctx context.Context) (int64, error) {
+	log := config.WithContext(ctx)
+
+	// o job roda perto da meia-noite local, que já é o dia seguinte em UTC
+	day := truncateDay(util.LocalNow())
+	count, err := s.repo.CaptureSnapshots(day)
+	if err != nil {
+		log.WithError(err).Error("Falha ao capturar snapshots diários dos projetos")
+		return 0, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"date":     day.Format(dateLayout),
+		"projects": count,
+	}).Info("Snapshots diários dos projetos capturados")
+	return count, nil
+}
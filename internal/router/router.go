@@ -6,9 +6,13 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/saulo-duarte/chronos-lambda/internal/assessment"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/flashcard"
 	"github.com/saulo-duarte/chronos-lambda/internal/middlewares"
+	"github.com/saulo-duarte/chronos-lambda/internal/milestone"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysession "github.com/saulo-duarte/chronos-lambda/internal/study_session"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
 	"github.com/saulo-duarte/chronos-lambda/internal/task"
@@ -18,9 +22,13 @@ import (
 type RouterConfig struct {
 	UserHandler         *user.Handler
 	ProjectHandler      *project.Handler
+	MilestoneHandler    *milestone.Handler
 	TaskHandler         *task.Handler
 	StudySubjectHandler *studysubject.Handler
 	StudyTopicHandler   *studytopic.Handler
+	FlashcardHandler    *flashcard.Handler
+	AssessmentHandler   *assessment.Handler
+	StudySessionHandler *studysession.Handler
 }
 
 func New(cfg RouterConfig) http.Handler {
@@ -32,18 +40,42 @@ func New(cfg RouterConfig) http.Handler {
 	r.Use(middleware.Recoverer)
 	r.Use(middlewares.CorsMiddleware)
 
+	r.Get("/.well-known/jwks.json", auth.JWKSHandler)
 	r.Mount("/users", user.Routes(cfg.UserHandler))
+	r.Mount("/auth", user.AuthRoutes(cfg.UserHandler))
 
 	r.Group(func(r chi.Router) {
 		r.Use(auth.AuthMiddleware)
+		r.Use(auth.RequirePermission(auth.PermissionManageOwnData))
 
 		r.Mount("/projects", project.Routes(cfg.ProjectHandler))
+		r.Mount("/projects/{projectId}/milestones", milestone.Routes(cfg.MilestoneHandler))
 		r.Mount("/tasks", task.Routes(cfg.TaskHandler))
 		r.Mount("/study-subjects", studysubject.Routes(cfg.StudySubjectHandler))
+		r.Mount("/terms", studysubject.TermRoutes(cfg.StudySubjectHandler))
 		r.Mount("/study-topics", studytopic.Routes(cfg.StudyTopicHandler))
+		r.Mount("/resources", studytopic.ResourceRoutes(cfg.StudyTopicHandler))
+		r.Mount("/study-topics/{studyTopicId}/flashcards", flashcard.TopicRoutes(cfg.FlashcardHandler))
+		r.Mount("/flashcards", flashcard.Routes(cfg.FlashcardHandler))
+		r.Mount("/study-subjects/{studySubjectId}/assessments", assessment.SubjectRoutes(cfg.AssessmentHandler))
+		r.Mount("/assessments", assessment.Routes(cfg.AssessmentHandler))
+		r.Mount("/study-sessions", studysession.Routes(cfg.StudySessionHandler))
 
 		r.Get("/study-subjects/{studySubjectId}/topics", cfg.StudyTopicHandler.ListStudyTopics)
+		r.Put("/study-subjects/{studySubjectId}/topics/order", cfg.StudyTopicHandler.ReorderStudyTopics)
+		r.Get("/study-subjects/{studySubjectId}/learning-path", cfg.StudyTopicHandler.GetLearningPath)
+		r.Post("/study-subjects/import", cfg.StudyTopicHandler.ImportSyllabus)
 		r.Get("/study-topics/{studyTopicId}/tasks", cfg.TaskHandler.ListTasksByStudyTopic)
+		r.Get("/projects/{projectId}/timeline", cfg.TaskHandler.GetProjectTimeline)
+		r.Post("/projects/{projectId}/clone", cfg.TaskHandler.CloneProject)
+		r.Post("/study-subjects/{studySubjectId}/clone", cfg.TaskHandler.CloneStudySubject)
+		r.Get("/reviews/due", cfg.TaskHandler.ListDueReviews)
+		r.Post("/study-subjects/{studySubjectId}/study-plan", cfg.TaskHandler.CreateStudyPlan)
+		r.Get("/study-plans/{planId}", cfg.TaskHandler.GetStudyPlan)
+		r.Post("/study-plans/{planId}/replan", cfg.TaskHandler.ReplanStudyPlan)
+		r.Get("/study-subjects/{studySubjectId}/flashcards/session", cfg.FlashcardHandler.GetReviewSession)
+		r.Get("/study-subjects/{studySubjectId}/flashcards/stats", cfg.FlashcardHandler.GetDeckStats)
+		r.Get("/study-subjects/{studySubjectId}/grades", cfg.AssessmentHandler.GetGradeSummary)
 	})
 	return r
 }
@@ -0,0 +1,310 @@
+package studysession
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+)
+
+type Granularity string
+
+const (
+	GROUP_DAY   Granularity = "day"
+	GROUP_WEEK  Granularity = "week"
+	GROUP_MONTH Granularity = "month"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// limite do intervalo consultado, para a resposta não crescer sem controle
+	maxAnalyticsDays = 731
+)
+
+var (
+	ErrInvalidGroup = errors.New("group must be day, week or month")
+	ErrInvalidRange = errors.New("from and to must be dates (YYYY-MM-DD), from cannot be after to and the range is limited to two years")
+)
+
+func (g Granularity) IsValid() bool {
+	switch g {
+	case GROUP_DAY, GROUP_WEEK, GROUP_MONTH:
+		return true
+	}
+	return false
+}
+
+type Analytics struct {
+	Group         Granularity            `json:"group"`
+	From          string                 `json:"from"`
+	To            string                 `json:"to"`
+	TotalMinutes  int                    `json:"total_minutes"`
+	TotalHours    float64                `json:"total_hours"`
+	Subjects      []SubjectHours         `json:"subjects"`
+	Periods       []Period               `json:"periods"`
+	Streak        Streak                 `json:"streak"`
+	WeeklyTargets []WeeklyTargetProgress `json:"weekly_targets"`
+}
+
+type Period struct {
+	Start        string         `json:"start"`
+	End          string         `json:"end"`
+	TotalMinutes int            `json:"total_minutes"`
+	TotalHours   float64        `json:"total_hours"`
+	Subjects     []SubjectHours `json:"subjects"`
+}
+
+// SubjectHours traz a meta semanal só quando o agrupamento é por semana.
+type SubjectHours struct {
+	SubjectID     uuid.UUID `json:"subject_id"`
+	Name          string    `json:"name"`
+	Minutes       int       `json:"minutes"`
+	Hours         float64   `json:"hours"`
+	TargetMinutes int       `json:"target_minutes,omitempty"`
+	TargetMet     *bool     `json:"target_met,omitempty"`
+}
+
+// Streak conta dias seguidos com pelo menos uma sessão. A sequência atual continua viva
+// até o fim do dia seguinte ao último estudo.
+type Streak struct {
+	Current      int     `json:"current"`
+	Longest      int     `json:"longest"`
+	LastStudyDay *string `json:"last_study_day"`
+	StudiedToday bool    `json:"studied_today"`
+}
+
+type WeeklyTargetProgress struct {
+	SubjectID        uuid.UUID `json:"subject_id"`
+	Name             string    `json:"name"`
+	WeekStart        string    `json:"week_start"`
+	TargetMinutes    int       `json:"target_minutes"`
+	Minutes          int       `json:"minutes"`
+	RemainingMinutes int       `json:"remaining_minutes"`
+	Percent          float64   `json:"percent"`
+	Met              bool      `json:"met"`
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart devolve o início do período que contém o dia; semanas começam na segunda.
+func periodStart(day time.Time, group Granularity) time.Time {
+	day = dayOf(day)
+	switch group {
+	case GROUP_WEEK:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case GROUP_MONTH:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextPeriod(start time.Time, group Granularity) time.Time {
+	switch group {
+	case GROUP_WEEK:
+		return start.AddDate(0, 0, 7)
+	case GROUP_MONTH:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// defaultFrom cobre os últimos 30 dias, 12 semanas ou 12 meses até o dia final.
+func defaultFrom(to time.Time, group Granularity) time.Time {
+	switch group {
+	case GROUP_WEEK:
+		return periodStart(to, GROUP_WEEK).AddDate(0, 0, -7*11)
+	case GROUP_MONTH:
+		return periodStart(to, GROUP_MONTH).AddDate(0, -11, 0)
+	}
+	return dayOf(to).AddDate(0, 0, -29)
+}
+
+// minutesByDay reparte a sessão entre os dias que ela atravessa, para que estudo depois
+// da meia-noite conte no dia certo.
+func minutesByDay(start, end time.Time) map[time.Time]time.Duration {
+	out := map[time.Time]time.Duration{}
+	for start.Before(end) {
+		next := dayOf(start).AddDate(0, 0, 1)
+		if next.After(end) {
+			next = end
+		}
+		out[dayOf(start)] += next.Sub(start)
+		start = next
+	}
+	return out
+}
+
+func toMinutes(d time.Duration) int {
+	return int(math.Round(d.Minutes()))
+}
+
+func toHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+// buildAnalytics agrega as sessões no intervalo [from, to], ambos dias inclusos.
+// Sessões que começam antes de from ou terminam depois de to contam só a parte de dentro.
+func buildAnalytics(group Granularity, from, to, today time.Time, sessions []*StudySession,
+	subjects []*studysubject.StudySubject, studyDays []time.Time) *Analytics {
+	from, to, today = dayOf(from), dayOf(to), dayOf(today)
+	end := to.AddDate(0, 0, 1)
+
+	byID := make(map[uuid.UUID]*studysubject.StudySubject, len(subjects))
+	for _, s := range subjects {
+		byID[s.ID] = s
+	}
+
+	perPeriod := map[time.Time]map[uuid.UUID]time.Duration{}
+	total := map[uuid.UUID]time.Duration{}
+	for _, s := range sessions {
+		for day, d := range minutesByDay(s.StartedAt.Time, s.EndedAt.Time) {
+			if day.Before(from) || !day.Before(end) {
+				continue
+			}
+			p := periodStart(day, group)
+			if perPeriod[p] == nil {
+				perPeriod[p] = map[uuid.UUID]time.Duration{}
+			}
+			perPeriod[p][s.SubjectID] += d
+			total[s.SubjectID] += d
+		}
+	}
+
+	a := &Analytics{
+		Group:         group,
+		From:          from.Format(dateLayout),
+		To:            to.Format(dateLayout),
+		Subjects:      subjectHours(total, byID, nil),
+		Periods:       []Period{},
+		Streak:        computeStreak(studyDays, today),
+		WeeklyTargets: weeklyTargets(sessions, subjects, periodStart(today, GROUP_WEEK)),
+	}
+	for _, sh := range a.Subjects {
+		a.TotalMinutes += sh.Minutes
+	}
+	a.TotalHours = toHours(a.TotalMinutes)
+
+	for p := periodStart(from, group); p.Before(end); p = nextPeriod(p, group) {
+		var targets []*studysubject.StudySubject
+		if group == GROUP_WEEK {
+			targets = subjects
+		}
+		period := Period{
+			Start:    p.Format(dateLayout),
+			End:      nextPeriod(p, group).AddDate(0, 0, -1).Format(dateLayout),
+			Subjects: subjectHours(perPeriod[p], byID, targets),
+		}
+		for _, sh := range period.Subjects {
+			period.TotalMinutes += sh.Minutes
+		}
+		period.TotalHours = toHours(period.TotalMinutes)
+		a.Periods = append(a.Periods, period)
+	}
+	return a
+}
+
+// subjectHours lista as matérias com tempo no período, da mais estudada para a menos.
+// Quando targets é informado, matérias com meta semanal aparecem mesmo sem estudo.
+func subjectHours(durations map[uuid.UUID]time.Duration, byID map[uuid.UUID]*studysubject.StudySubject,
+	targets []*studysubject.StudySubject) []SubjectHours {
+	out := []SubjectHours{}
+	seen := map[uuid.UUID]bool{}
+	for id, d := range durations {
+		sh := SubjectHours{SubjectID: id, Minutes: toMinutes(d)}
+		if s := byID[id]; s != nil {
+			sh.Name = s.Name
+		}
+		out = append(out, sh)
+		seen[id] = true
+	}
+	for _, s := range targets {
+		if s.WeeklyTarget > 0 && !seen[s.ID] && !s.Archived {
+			out = append(out, SubjectHours{SubjectID: s.ID, Name: s.Name})
+		}
+	}
+	for i := range out {
+		out[i].Hours = toHours(out[i].Minutes)
+		if s := byID[out[i].SubjectID]; targets != nil && s != nil && s.WeeklyTarget > 0 {
+			met := out[i].Minutes >= s.WeeklyTarget
+			out[i].TargetMinutes = s.WeeklyTarget
+			out[i].TargetMet = &met
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Minutes != out[j].Minutes {
+			return out[i].Minutes > out[j].Minutes
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// computeStreak espera os dias estudados em ordem crescente e sem repetição.
+func computeStreak(days []time.Time, today time.Time) Streak {
+	var st Streak
+	if len(days) == 0 {
+		return st
+	}
+
+	run := 0
+	var prev time.Time
+	for i, day := range days {
+		day = dayOf(day)
+		if i > 0 && day.Equal(prev.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > st.Longest {
+			st.Longest = run
+		}
+		prev = day
+	}
+
+	last := prev.Format(dateLayout)
+	st.LastStudyDay = &last
+	st.StudiedToday = prev.Equal(today)
+	if st.StudiedToday || prev.Equal(today.AddDate(0, 0, -1)) {
+		st.Current = run
+	}
+	return st
+}
+
+// weeklyTargets compara o estudo da semana que começa em weekStart com a meta de cada matéria ativa.
+func weeklyTargets(sessions []*StudySession, subjects []*studysubject.StudySubject, weekStart time.Time) []WeeklyTargetProgress {
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	studied := map[uuid.UUID]time.Duration{}
+	for _, s := range sessions {
+		for day, d := range minutesByDay(s.StartedAt.Time, s.EndedAt.Time) {
+			if !day.Before(weekStart) && day.Before(weekEnd) {
+				studied[s.SubjectID] += d
+			}
+		}
+	}
+
+	out := []WeeklyTargetProgress{}
+	for _, s := range subjects {
+		if s.WeeklyTarget <= 0 || s.Archived {
+			continue
+		}
+		p := WeeklyTargetProgress{
+			SubjectID:     s.ID,
+			Name:          s.Name,
+			WeekStart:     weekStart.Format(dateLayout),
+			TargetMinutes: s.WeeklyTarget,
+			Minutes:       toMinutes(studied[s.ID]),
+		}
+		p.RemainingMinutes = max(p.TargetMinutes-p.Minutes, 0)
+		p.Percent = math.Round(float64(p.Minutes)/float64(p.TargetMinutes)*10000) / 100
+		p.Met = p.Minutes >= p.TargetMinutes
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,21 @@
+package studysession
+
+import (
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"gorm.io/gorm"
+)
+
+type StudySessionContainer struct {
+	Handler *Handler
+}
+
+func NewStudySessionContainer(db *gorm.DB, topicRepo studytopic.StudyTopicRepository) *StudySessionContainer {
+	repo := NewRepository(db)
+	service := NewService(repo, topicRepo, studysubject.NewRepository(db))
+	handler := NewHandler(service)
+
+	return &StudySessionContainer{
+		Handler: handler,
+	}
+}
@@ -0,0 +1,48 @@
+package studysession
+
+import (
+	"errors"
+	"time"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const (
+	minFocus = 1
+	maxFocus = 5
+	// uma sessão maior que isso quase sempre é um cronômetro esquecido ligado
+	maxSessionDuration = 16 * time.Hour
+)
+
+var (
+	ErrInvalidSession = errors.New("topic, start and end are required and the session must last between 1 minute and 16 hours")
+	ErrInvalidFocus   = errors.New("focus must be between 1 and 5")
+	ErrSessionOverlap = errors.New("session overlaps another study session")
+)
+
+type StudySessionDTO struct {
+	TopicID   string              `json:"topic_id"`
+	StartedAt *util.LocalDateTime `json:"started_at"`
+	EndedAt   *util.LocalDateTime `json:"ended_at"`
+	Notes     string              `json:"notes"`
+	Focus     *int                `json:"focus"`
+}
+
+func (dto *StudySessionDTO) Validate() error {
+	if dto.TopicID == "" || dto.StartedAt == nil || dto.StartedAt.IsZero() || dto.EndedAt == nil || dto.EndedAt.IsZero() {
+		return ErrInvalidSession
+	}
+	d := dto.EndedAt.Sub(dto.StartedAt.Time)
+	if d < time.Minute || d > maxSessionDuration {
+		return ErrInvalidSession
+	}
+	if dto.Focus != nil && (*dto.Focus < minFocus || *dto.Focus > maxFocus) {
+		return ErrInvalidFocus
+	}
+	return nil
+}
+
+// durationMinutes arredonda para baixo; segundos soltos não contam.
+func durationMinutes(start, end time.Time) int {
+	return int(end.Sub(start) / time.Minute)
+}
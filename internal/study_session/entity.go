@@ -0,0 +1,24 @@
+package studysession
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+// StudySession registra um bloco de estudo já realizado. SubjectID é guardado junto para que
+// as horas continuem contando para a matéria mesmo se o tópico for removido.
+type StudySession struct {
+	ID        uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TopicID   *uuid.UUID          `gorm:"type:uuid;index" json:"topic_id"`
+	SubjectID uuid.UUID           `gorm:"column:subject_id;not null;index" json:"subject_id"`
+	UserID    uuid.UUID           `gorm:"column:user_id;not null;index:idx_study_sessions_user_started,priority:1" json:"user_id"`
+	StartedAt *util.LocalDateTime `gorm:"not null;index:idx_study_sessions_user_started,priority:2" json:"started_at"`
+	EndedAt   *util.LocalDateTime `gorm:"not null" json:"ended_at"`
+	Minutes   int                 `gorm:"not null" json:"minutes"`
+	Notes     string              `json:"notes"`
+	Focus     *int                `json:"focus"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
@@ -0,0 +1,132 @@
+package studysession
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+type Handler struct {
+	service StudySessionService
+}
+
+func NewHandler(s StudySessionService) *Handler {
+	return &Handler{service: s}
+}
+
+func (h *Handler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload StudySessionDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.CreateSession(r.Context(), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error creating study session")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, session)
+}
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	sessions, err := h.service.ListSessions(r.Context(), ListQuery{
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+		SubjectID: query.Get("subject_id"),
+		TopicID:   query.Get("topic_id"),
+	})
+	if err != nil {
+		writeError(w, r, err, "Error listing study sessions")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":    len(sessions),
+		"sessions": sessions,
+	})
+}
+
+func (h *Handler) GetSession(w http.ResponseWriter, r *http.Request) {
+	session, err := h.service.GetSession(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err, "Error fetching study session")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) UpdateSession(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload StudySessionDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.UpdateSession(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeError(w, r, err, "Error updating study session")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteSession(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err, "Error deleting study session")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "study session deleted successfully",
+	})
+}
+
+func (h *Handler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	analytics, err := h.service.GetAnalytics(r.Context(), AnalyticsQuery{
+		Group: query.Get("group"),
+		From:  query.Get("from"),
+		To:    query.Get("to"),
+	})
+	if err != nil {
+		writeError(w, r, err, "Error computing study analytics")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, analytics)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrSessionNotFound):
+		http.Error(w, "study session not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudyTopicNotFound):
+		http.Error(w, "study topic not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrSessionOverlap):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrInvalidSession), errors.Is(err, ErrInvalidFocus),
+		errors.Is(err, ErrInvalidGroup), errors.Is(err, ErrInvalidRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
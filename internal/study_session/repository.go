@@ -0,0 +1,100 @@
+package studysession
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionFilter restringe a listagem; campos nulos não filtram.
+type SessionFilter struct {
+	From      *time.Time
+	To        *time.Time
+	SubjectID *uuid.UUID
+	TopicID   *uuid.UUID
+}
+
+type StudySessionRepository interface {
+	Create(s *StudySession) error
+	Update(s *StudySession) error
+	Delete(id uuid.UUID) error
+	GetByID(id string) (*StudySession, error)
+	ListByUser(userID uuid.UUID, filter SessionFilter) ([]*StudySession, error)
+	HasOverlap(userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) (bool, error)
+	ListStudyDays(userID uuid.UUID) ([]time.Time, error)
+}
+
+type studySessionRepository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) StudySessionRepository {
+	return &studySessionRepository{db: db}
+}
+
+func (r *studySessionRepository) Create(s *StudySession) error {
+	return r.db.Create(s).Error
+}
+
+func (r *studySessionRepository) Update(s *StudySession) error {
+	return r.db.Save(s).Error
+}
+
+func (r *studySessionRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&StudySession{}, "id = ?", id).Error
+}
+
+func (r *studySessionRepository) GetByID(id string) (*StudySession, error) {
+	var s StudySession
+	if err := r.db.First(&s, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByUser devolve as sessões que tocam o intervalo [From, To), mais recentes primeiro.
+func (r *studySessionRepository) ListByUser(userID uuid.UUID, filter SessionFilter) ([]*StudySession, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if filter.From != nil {
+		query = query.Where("ended_at > ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("started_at < ?", *filter.To)
+	}
+	if filter.SubjectID != nil {
+		query = query.Where("subject_id = ?", *filter.SubjectID)
+	}
+	if filter.TopicID != nil {
+		query = query.Where("topic_id = ?", *filter.TopicID)
+	}
+
+	var sessions []*StudySession
+	if err := query.Order("started_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *studySessionRepository) HasOverlap(userID uuid.UUID, start, end time.Time, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&StudySession{}).
+		Where("user_id = ? AND id <> ? AND started_at < ? AND ended_at > ?", userID, excludeID, end, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListStudyDays devolve os dias com pelo menos uma sessão, em ordem crescente. Como uma sessão
+// dura no máximo 16 horas, basta olhar o dia do início e o do fim.
+func (r *studySessionRepository) ListStudyDays(userID uuid.UUID) ([]time.Time, error) {
+	var days []time.Time
+	err := r.db.Raw(`SELECT CAST(started_at AS date) AS day FROM study_sessions WHERE user_id = ?
+		UNION
+		SELECT CAST(ended_at AS date) FROM study_sessions WHERE user_id = ? AND ended_at > CAST(ended_at AS date)
+		ORDER BY day`, userID, userID).Scan(&days).Error
+	return days, err
+}
@@ -0,0 +1,23 @@
+package studysession
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+)
+
+func Routes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Post("/", h.CreateSession)
+	r.Get("/", h.ListSessions)
+	r.Get("/analytics", h.GetAnalytics)
+	r.Get("/{id}", h.GetSession)
+	r.Put("/{id}", h.UpdateSession)
+	r.Delete("/{id}", h.DeleteSession)
+
+	return r
+}
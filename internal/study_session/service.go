@@ -0,0 +1,357 @@
+package studysession
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrSessionNotFound      = errors.New("study session not found")
+	ErrStudyTopicNotFound   = studytopic.ErrStudyTopicNotFound
+	ErrStudySubjectNotFound = studysubject.ErrStudySubjectNotFound
+	ErrUnauthorized         = errors.New("unauthorized")
+)
+
+// ListQuery e AnalyticsQuery chegam crus da query string; datas no formato YYYY-MM-DD.
+type ListQuery struct {
+	From      string
+	To        string
+	SubjectID string
+	TopicID   string
+}
+
+type AnalyticsQuery struct {
+	Group string
+	From  string
+	To    string
+}
+
+type StudySessionService interface {
+	CreateSession(ctx context.Context, dto *StudySessionDTO) (*StudySession, error)
+	GetSession(ctx context.Context, id string) (*StudySession, error)
+	ListSessions(ctx context.Context, q ListQuery) ([]*StudySession, error)
+	UpdateSession(ctx context.Context, id string, dto *StudySessionDTO) (*StudySession, error)
+	DeleteSession(ctx context.Context, id string) error
+	GetAnalytics(ctx context.Context, q AnalyticsQuery) (*Analytics, error)
+}
+
+type studySessionService struct {
+	repo        StudySessionRepository
+	topicRepo   studytopic.StudyTopicRepository
+	subjectRepo studysubject.StudySubjectRepository
+}
+
+func NewService(repo StudySessionRepository, topicRepo studytopic.StudyTopicRepository, subjectRepo studysubject.StudySubjectRepository) StudySessionService {
+	return &studySessionService{repo: repo, topicRepo: topicRepo, subjectRepo: subjectRepo}
+}
+
+func currentUserID(ctx context.Context, log logrus.FieldLogger, action string) (uuid.UUID, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("Attempt to %s without authentication", action)
+		return uuid.Nil, ErrUnauthorized
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		log.WithError(err).Warnf("Attempt to %s with invalid user ID", action)
+		return uuid.Nil, ErrUnauthorized
+	}
+	return userID, nil
+}
+
+func (s *studySessionService) getOwnedTopic(ctx context.Context, userID uuid.UUID, topicID string) (*studytopic.StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	if _, err := uuid.Parse(topicID); err != nil {
+		return nil, ErrStudyTopicNotFound
+	}
+	topic, err := s.topicRepo.GetByID(topicID)
+	if err != nil {
+		log.WithError(err).Error("Error fetching study topic for session")
+		return nil, err
+	}
+	if topic == nil {
+		return nil, ErrStudyTopicNotFound
+	}
+	if !auth.IsOwner(userID.String(), topic.UserID) {
+		log.WithFields(logrus.Fields{
+			"topic_id": topic.ID,
+			"user_id":  userID,
+		}).Warn("User attempted to log a session on another user's topic")
+		return nil, ErrUnauthorized
+	}
+	return topic, nil
+}
+
+func (s *studySessionService) getOwnedSession(ctx context.Context, id, action string) (*StudySession, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	session, err := s.repo.GetByID(id)
+	if err != nil {
+		log.WithError(err).Error("Error fetching study session by ID")
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+	if !auth.IsOwner(userID.String(), session.UserID) {
+		log.WithFields(logrus.Fields{
+			"session_id": session.ID,
+			"user_id":    userID,
+		}).Warnf("User attempted to %s another user's study session", action)
+		return nil, ErrUnauthorized
+	}
+	return session, nil
+}
+
+// apply valida o tópico e a sobreposição com outras sessões antes de copiar o DTO.
+func (s *studySessionService) apply(ctx context.Context, session *StudySession, dto *StudySessionDTO) error {
+	log := config.WithContext(ctx)
+
+	topic, err := s.getOwnedTopic(ctx, session.UserID, dto.TopicID)
+	if err != nil {
+		return err
+	}
+
+	overlap, err := s.repo.HasOverlap(session.UserID, dto.StartedAt.Time, dto.EndedAt.Time, session.ID)
+	if err != nil {
+		log.WithError(err).Error("Error checking study session overlap")
+		return err
+	}
+	if overlap {
+		return ErrSessionOverlap
+	}
+
+	session.TopicID = &topic.ID
+	session.SubjectID = topic.StudySubjectID
+	session.StartedAt = dto.StartedAt
+	session.EndedAt = dto.EndedAt
+	session.Minutes = durationMinutes(dto.StartedAt.Time, dto.EndedAt.Time)
+	session.Notes = strings.TrimSpace(dto.Notes)
+	session.Focus = dto.Focus
+	return nil
+}
+
+func (s *studySessionService) CreateSession(ctx context.Context, dto *StudySessionDTO) (*StudySession, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, "create study session")
+	if err != nil {
+		return nil, err
+	}
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &StudySession{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.apply(ctx, session, dto); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(session); err != nil {
+		log.WithError(err).Error("Failed to create study session")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"session_id": session.ID,
+		"subject_id": session.SubjectID,
+		"minutes":    session.Minutes,
+	}).Info("Study session created successfully")
+	return session, nil
+}
+
+func (s *studySessionService) GetSession(ctx context.Context, id string) (*StudySession, error) {
+	return s.getOwnedSession(ctx, id, "access")
+}
+
+func parseDay(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	day, err := time.Parse(dateLayout, raw)
+	if err != nil {
+		return nil, ErrInvalidRange
+	}
+	return &day, nil
+}
+
+func parseOptionalID(raw string, notFound error) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, notFound
+	}
+	return &id, nil
+}
+
+func (s *studySessionService) ListSessions(ctx context.Context, q ListQuery) ([]*StudySession, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, "list study sessions")
+	if err != nil {
+		return nil, err
+	}
+
+	var filter SessionFilter
+	if filter.From, err = parseDay(q.From); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseDay(q.To); err != nil {
+		return nil, err
+	}
+	if filter.To != nil {
+		// o dia final é inclusivo
+		end := filter.To.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidRange
+	}
+	if filter.SubjectID, err = parseOptionalID(q.SubjectID, ErrStudySubjectNotFound); err != nil {
+		return nil, err
+	}
+	if filter.TopicID, err = parseOptionalID(q.TopicID, ErrStudyTopicNotFound); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.repo.ListByUser(userID, filter)
+	if err != nil {
+		log.WithError(err).Error("Error listing study sessions")
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *studySessionService) UpdateSession(ctx context.Context, id string, dto *StudySessionDTO) (*StudySession, error) {
+	log := config.WithContext(ctx)
+
+	session, err := s.getOwnedSession(ctx, id, "update")
+	if err != nil {
+		return nil, err
+	}
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, session, dto); err != nil {
+		return nil, err
+	}
+	session.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(session); err != nil {
+		log.WithError(err).Error("Failed to update study session")
+		return nil, err
+	}
+
+	log.WithField("session_id", session.ID).Info("Study session updated successfully")
+	return session, nil
+}
+
+func (s *studySessionService) DeleteSession(ctx context.Context, id string) error {
+	log := config.WithContext(ctx)
+
+	session, err := s.getOwnedSession(ctx, id, "delete")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(session.ID); err != nil {
+		log.WithError(err).Error("Failed to delete study session")
+		return err
+	}
+
+	log.WithField("session_id", session.ID).Info("Study session deleted successfully")
+	return nil
+}
+
+func (s *studySessionService) GetAnalytics(ctx context.Context, q AnalyticsQuery) (*Analytics, error) {
+	log := config.WithContext(ctx)
+
+	userID, err := currentUserID(ctx, log, "access study analytics")
+	if err != nil {
+		return nil, err
+	}
+
+	group := Granularity(q.Group)
+	if group == "" {
+		group = GROUP_WEEK
+	}
+	if !group.IsValid() {
+		return nil, ErrInvalidGroup
+	}
+
+	today := dayOf(util.LocalNow())
+	to, err := parseDay(q.To)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		to = &today
+	}
+	from, err := parseDay(q.From)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		d := defaultFrom(*to, group)
+		from = &d
+	}
+	if from.After(*to) || to.Sub(*from) > maxAnalyticsDays*24*time.Hour {
+		return nil, ErrInvalidRange
+	}
+
+	// a busca também cobre a semana atual, usada na comparação com as metas
+	weekStart := periodStart(today, GROUP_WEEK)
+	queryFrom := *from
+	if weekStart.Before(queryFrom) {
+		queryFrom = weekStart
+	}
+	queryTo := to.AddDate(0, 0, 1)
+	if weekEnd := weekStart.AddDate(0, 0, 7); weekEnd.After(queryTo) {
+		queryTo = weekEnd
+	}
+
+	sessions, err := s.repo.ListByUser(userID, SessionFilter{From: &queryFrom, To: &queryTo})
+	if err != nil {
+		log.WithError(err).Error("Error listing study sessions for analytics")
+		return nil, err
+	}
+	subjects, err := s.subjectRepo.ListByUser(userID.String(), studysubject.SubjectFilter{IncludeArchived: true})
+	if err != nil {
+		log.WithError(err).Error("Error listing study subjects for analytics")
+		return nil, err
+	}
+	days, err := s.repo.ListStudyDays(userID)
+	if err != nil {
+		log.WithError(err).Error("Error listing study days for analytics")
+		return nil, err
+	}
+
+	return buildAnalytics(group, *from, *to, today, sessions, subjects, days), nil
+}
@@ -0,0 +1,39 @@
+package studysubject
+
+import (
+	"errors"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+// uma semana inteira em minutos
+const maxWeeklyTargetMinutes = 7 * 24 * 60
+
+var ErrInvalidWeeklyTarget = errors.New("weekly target must be between 0 and 10080 minutes")
+
+type DeleteOptions struct {
+	Strategy        util.DeleteStrategy
+	TargetSubjectID string
+}
+
+type DeletionImpact struct {
+	Topics      int64 `json:"topics"`
+	Tasks       int64 `json:"tasks"`
+	Assessments int64 `json:"assessments"`
+	Sessions    int64 `json:"sessions"`
+}
+
+func (i *DeletionImpact) HasChildren() bool {
+	return i.Topics > 0 || i.Tasks > 0 || i.Assessments > 0 || i.Sessions > 0
+}
+
+type WeeklyTargetDTO struct {
+	WeeklyTargetMinutes int `json:"weekly_target_minutes"`
+}
+
+func (dto *WeeklyTargetDTO) Validate() error {
+	if dto.WeeklyTargetMinutes < 0 || dto.WeeklyTargetMinutes > maxWeeklyTargetMinutes {
+		return ErrInvalidWeeklyTarget
+	}
+	return nil
+}
@@ -8,11 +8,34 @@ import (
 )
 
 type StudySubject struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	UserID      uuid.UUID `gorm:"column:user_id;not null" json:"user_id"`
-	User        user.User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	TermID      *uuid.UUID `gorm:"type:uuid;index" json:"term_id"`
+	Archived    bool       `gorm:"not null;default:false" json:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at"`
+	// WeeklyTarget é a meta de minutos de estudo por semana; zero significa sem meta.
+	WeeklyTarget int       `gorm:"column:weekly_target_minutes;not null;default:0" json:"weekly_target_minutes"`
+	UserID       uuid.UUID `gorm:"column:user_id;not null" json:"user_id"`
+	User         user.User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Progress     *Progress `gorm:"-" json:"progress,omitempty"`
+}
+
+type Progress struct {
+	TotalTopics      int64      `json:"total_topics"`
+	NotStartedTopics int64      `json:"not_started_topics"`
+	LearningTopics   int64      `json:"learning_topics"`
+	ReviewedTopics   int64      `json:"reviewed_topics"`
+	MasteredTopics   int64      `json:"mastered_topics"`
+	PercentMastered  float64    `json:"percent_mastered"`
+	PercentComplete  float64    `json:"percent_complete"`
+	TotalStudyTasks  int64      `json:"total_study_tasks"`
+	DoneStudyTasks   int64      `json:"done_study_tasks"`
+	PercentTasksDone float64    `json:"percent_tasks_done"`
+	TotalResources   int64      `json:"total_resources"`
+	DoneResources    int64      `json:"done_resources"`
+	PercentResources float64    `json:"percent_resources"`
+	LastMasteredAt   *time.Time `json:"last_mastered_at"`
 }
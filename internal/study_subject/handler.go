@@ -2,12 +2,14 @@ package studysubject
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 )
 
 type Handler struct {
@@ -34,6 +36,10 @@ func (h *Handler) CreateStudySubject(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 			return
 		}
+		if err == ErrTermNotFound {
+			http.Error(w, "term not found", http.StatusNotFound)
+			return
+		}
 		log.WithError(err).Error("Error creating study subject")
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -52,12 +58,21 @@ func (h *Handler) ListStudySubjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	subjects, err := h.service.ListStudySubjectsByUser(r.Context(), claims.UserID)
+	opts := ListOptions{
+		Term:            r.URL.Query().Get("term"),
+		IncludeArchived: r.URL.Query().Get("archived") == "true",
+	}
+
+	subjects, err := h.service.ListStudySubjectsByUser(r.Context(), claims.UserID, opts)
 	if err != nil {
 		if err == ErrUnauthorized {
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 			return
 		}
+		if err == ErrTermNotFound {
+			http.Error(w, "term not found", http.StatusNotFound)
+			return
+		}
 		log.WithError(err).Error("Error listing study subjects")
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -69,6 +84,33 @@ func (h *Handler) ListStudySubjects(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) GetStudySubject(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	subjectID := chi.URLParam(r, "id")
+	if subjectID == "" {
+		log.Warn("Study subject ID not provided")
+		http.Error(w, "study subject id required", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.GetStudySubject(r.Context(), subjectID)
+	if err != nil {
+		switch err {
+		case ErrStudySubjectNotFound:
+			http.Error(w, "study subject not found", http.StatusNotFound)
+		case ErrUnauthorized:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		default:
+			log.WithError(err).Error("Error fetching study subject")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, subject)
+}
+
 func (h *Handler) UpdateStudySubject(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 
@@ -115,12 +157,27 @@ func (h *Handler) DeleteStudySubject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteStudySubject(r.Context(), subjectID); err != nil {
+	strategy, err := util.ParseDeleteStrategy(r.URL.Query().Get("strategy"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := DeleteOptions{
+		Strategy:        strategy,
+		TargetSubjectID: r.URL.Query().Get("target"),
+	}
+
+	if err := h.service.DeleteStudySubject(r.Context(), subjectID, opts); err != nil {
 		switch err {
 		case ErrStudySubjectNotFound:
 			http.Error(w, "study subject not found", http.StatusNotFound)
 		case ErrUnauthorized:
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case ErrInvalidReassignTarget, util.ErrInvalidDeleteStrategy:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case ErrStudySubjectHasChildren:
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			log.WithError(err).Error("Error deleting study subject")
 			http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -132,3 +189,175 @@ func (h *Handler) DeleteStudySubject(w http.ResponseWriter, r *http.Request) {
 		"message": "study subject deleted successfully",
 	})
 }
+
+func (h *Handler) PreviewDeletion(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	subjectID := chi.URLParam(r, "id")
+	if subjectID == "" {
+		log.Warn("Study subject ID not provided")
+		http.Error(w, "study subject id required", http.StatusBadRequest)
+		return
+	}
+
+	impact, err := h.service.PreviewDeletion(r.Context(), subjectID)
+	if err != nil {
+		switch err {
+		case ErrStudySubjectNotFound:
+			http.Error(w, "study subject not found", http.StatusNotFound)
+		case ErrUnauthorized:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		default:
+			log.WithError(err).Error("Error previewing study subject deletion")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, impact)
+}
+
+func (h *Handler) SetArchived(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ArchiveDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.SetArchived(r.Context(), chi.URLParam(r, "id"), payload.Archived)
+	if err != nil {
+		writeTermError(w, r, err, "Error archiving study subject")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, subject)
+}
+
+func (h *Handler) AssignTerm(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload AssignTermDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.AssignTerm(r.Context(), chi.URLParam(r, "id"), payload.TermID)
+	if err != nil {
+		writeTermError(w, r, err, "Error assigning term to study subject")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, subject)
+}
+
+func (h *Handler) CreateTerm(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload TermDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	term, err := h.service.CreateTerm(r.Context(), &payload)
+	if err != nil {
+		writeTermError(w, r, err, "Error creating term")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, term)
+}
+
+func (h *Handler) ListTerms(w http.ResponseWriter, r *http.Request) {
+	terms, err := h.service.ListTerms(r.Context())
+	if err != nil {
+		writeTermError(w, r, err, "Error listing terms")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count": len(terms),
+		"terms": terms,
+	})
+}
+
+func (h *Handler) GetTerm(w http.ResponseWriter, r *http.Request) {
+	term, err := h.service.GetTerm(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeTermError(w, r, err, "Error fetching term")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, term)
+}
+
+func (h *Handler) UpdateTerm(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload TermDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	term, err := h.service.UpdateTerm(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeTermError(w, r, err, "Error updating term")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, term)
+}
+
+func (h *Handler) DeleteTerm(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteTerm(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeTermError(w, r, err, "Error deleting term")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "term deleted successfully",
+	})
+}
+
+func (h *Handler) SetWeeklyTarget(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload WeeklyTargetDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.SetWeeklyTarget(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeTermError(w, r, err, "Error updating study subject weekly target")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, subject)
+}
+
+func writeTermError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrTermNotFound):
+		http.Error(w, "term not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidTerm), errors.Is(err, ErrInvalidWeeklyTarget):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -2,16 +2,32 @@ package studysubject
 
 import (
 	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 
 	"gorm.io/gorm"
 )
 
 type StudySubjectRepository interface {
 	Create(s *StudySubject) error
-	ListByUser(userID string) ([]*StudySubject, error)
+	ListByUser(userID string, filter SubjectFilter) ([]*StudySubject, error)
 	Update(s *StudySubject) error
 	Delete(id string) error
 	GetByID(id string) (*StudySubject, error)
+	CountChildren(id string) (*DeletionImpact, error)
+	DeleteWithStrategy(id string, strategy util.DeleteStrategy, targetID string) error
+	GetProgress(subjectIDs []uuid.UUID) (map[uuid.UUID]*Progress, error)
+	CreateTerm(t *Term) error
+	GetTerm(id string) (*Term, error)
+	ListTerms(userID uuid.UUID) ([]*Term, error)
+	UpdateTerm(t *Term) error
+	DeleteTerm(id uuid.UUID) error
+	ActiveTerm(userID uuid.UUID, day time.Time) (*Term, error)
+	GetTermSummaries(termIDs []uuid.UUID) (map[uuid.UUID]*TermSummary, error)
 }
 
 type studySubjectRepository struct {
@@ -26,9 +42,20 @@ func (r *studySubjectRepository) Create(s *StudySubject) error {
 	return r.db.Create(s).Error
 }
 
-func (r *studySubjectRepository) ListByUser(userID string) ([]*StudySubject, error) {
+func (r *studySubjectRepository) ListByUser(userID string, filter SubjectFilter) ([]*StudySubject, error) {
+	query := r.db.Where("user_id = ?", userID)
+	switch {
+	case filter.TermID != nil:
+		query = query.Where("term_id = ?", *filter.TermID)
+	case filter.Unassigned:
+		query = query.Where("term_id IS NULL")
+	}
+	if !filter.IncludeArchived {
+		query = query.Where("archived = ?", false)
+	}
+
 	var subjects []*StudySubject
-	if err := r.db.Where("user_id = ?", userID).Find(&subjects).Error; err != nil {
+	if err := query.Find(&subjects).Error; err != nil {
 		return nil, err
 	}
 	return subjects, nil
@@ -52,3 +79,377 @@ func (r *studySubjectRepository) GetByID(id string) (*StudySubject, error) {
 	}
 	return &subject, nil
 }
+
+func (r *studySubjectRepository) CountChildren(id string) (*DeletionImpact, error) {
+	return countChildren(r.db, id)
+}
+
+func countChildren(db *gorm.DB, id string) (*DeletionImpact, error) {
+	var impact DeletionImpact
+	if err := db.Table("study_topics").Where("subject_id = ?", id).Count(&impact.Topics).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("tasks").
+		Where("study_topic_id IN (?)", db.Table("study_topics").Select("id").Where("subject_id = ?", id)).
+		Count(&impact.Tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("assessments").Where("subject_id = ?", id).Count(&impact.Assessments).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("study_sessions").Where("subject_id = ?", id).Count(&impact.Sessions).Error; err != nil {
+		return nil, err
+	}
+	return &impact, nil
+}
+
+// DeleteWithStrategy remove o assunto tratando tópicos e suas tasks numa única transação.
+// Tópicos não existem sem assunto, então "detach" não é suportado aqui.
+func (r *studySubjectRepository) DeleteWithStrategy(id string, strategy util.DeleteStrategy, targetID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		switch strategy {
+		case util.DeleteCascade:
+			if err := tx.Exec(`DELETE FROM task_dependencies
+				WHERE task_id IN (SELECT t.id FROM tasks t JOIN study_topics st ON st.id = t.study_topic_id WHERE st.subject_id = ?)
+				OR depends_on_id IN (SELECT t.id FROM tasks t JOIN study_topics st ON st.id = t.study_topic_id WHERE st.subject_id = ?)`, id, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM tasks WHERE study_topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM topic_reviews WHERE topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM flashcards WHERE topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM resources WHERE topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`DELETE FROM topic_prerequisites
+				WHERE topic_id IN (SELECT id FROM study_topics WHERE subject_id = ?)
+				OR prerequisite_id IN (SELECT id FROM study_topics WHERE subject_id = ?)`, id, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM study_topics WHERE subject_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM tasks WHERE assessment_id IN (SELECT id FROM assessments WHERE subject_id = ?)", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM assessment_topics WHERE assessment_id IN (SELECT id FROM assessments WHERE subject_id = ?)", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM assessments WHERE subject_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM study_sessions WHERE subject_id = ?", id).Error; err != nil {
+				return err
+			}
+		case util.DeleteReassign:
+			// desloca as posições para não colidir com os tópicos já existentes no destino
+			if err := tx.Exec(`UPDATE study_topics
+				SET subject_id = ?, position = position + (SELECT COALESCE(MAX(position), 0) FROM study_topics WHERE subject_id = ?)
+				WHERE subject_id = ?`, targetID, targetID, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE assessments SET subject_id = ? WHERE subject_id = ?", targetID, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE study_sessions SET subject_id = ? WHERE subject_id = ?", targetID, id).Error; err != nil {
+				return err
+			}
+
+		case util.DeleteBlock:
+			impact, err := countChildren(tx, id)
+			if err != nil {
+				return err
+			}
+			if impact.HasChildren() {
+				return ErrStudySubjectHasChildren
+			}
+		default:
+			return util.ErrInvalidDeleteStrategy
+		}
+
+		// o plano de estudos é da matéria; sessões que sobrarem viram tasks avulsas
+		if err := tx.Exec("UPDATE tasks SET study_plan_id = NULL WHERE study_plan_id IN (SELECT id FROM study_plans WHERE subject_id = ?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM study_plans WHERE subject_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&StudySubject{}, "id = ?", id).Error
+	})
+}
+
+type topicProgressRow struct {
+	SubjectID      uuid.UUID
+	TotalTopics    int64
+	NotStarted     int64
+	Learning       int64
+	Reviewed       int64
+	Mastered       int64
+	LastMasteredAt *time.Time
+}
+
+type taskProgressRow struct {
+	SubjectID  uuid.UUID
+	TotalTasks int64
+	DoneTasks  int64
+}
+
+type resourceProgressRow struct {
+	SubjectID uuid.UUID
+	Total     int64
+	Done      int64
+	Ratio     float64
+}
+
+// GetProgress agrega tópicos, tasks STUDY e materiais de cada assunto sem carregar os registros.
+func (r *studySubjectRepository) GetProgress(subjectIDs []uuid.UUID) (map[uuid.UUID]*Progress, error) {
+	progress := make(map[uuid.UUID]*Progress, len(subjectIDs))
+	if len(subjectIDs) == 0 {
+		return progress, nil
+	}
+
+	var topics []topicProgressRow
+	err := r.db.Table("study_topics").
+		Select(`subject_id,
+			COUNT(*) AS total_topics,
+			COUNT(*) FILTER (WHERE mastery = 'NOT_STARTED' OR mastery IS NULL) AS not_started,
+			COUNT(*) FILTER (WHERE mastery = 'LEARNING') AS learning,
+			COUNT(*) FILTER (WHERE mastery = 'REVIEWED') AS reviewed,
+			COUNT(*) FILTER (WHERE mastery = 'MASTERED') AS mastered,
+			MAX(mastered_at) AS last_mastered_at`).
+		Where("subject_id IN ?", subjectIDs).
+		Group("subject_id").
+		Scan(&topics).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []taskProgressRow
+	err = r.db.Table("tasks t").
+		Select(`st.subject_id,
+			COUNT(*) AS total_tasks,
+			COUNT(*) FILTER (WHERE t.status = 'DONE') AS done_tasks`).
+		Joins("JOIN study_topics st ON st.id = t.study_topic_id").
+		Where("t.type = 'STUDY' AND st.subject_id IN ?", subjectIDs).
+		Group("st.subject_id").
+		Scan(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// como as unidades variam entre materiais, o percentual é a média do percentual de cada um
+	var resources []resourceProgressRow
+	err = r.db.Table("resources res").
+		Select(`st.subject_id,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE res.completed_units >= res.total_units) AS done,
+			COALESCE(AVG(LEAST(res.completed_units::float / NULLIF(res.total_units, 0), 1)), 0) AS ratio`).
+		Joins("JOIN study_topics st ON st.id = res.topic_id").
+		Where("st.subject_id IN ?", subjectIDs).
+		Group("st.subject_id").
+		Scan(&resources).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range subjectIDs {
+		progress[id] = &Progress{}
+	}
+	for _, row := range topics {
+		p := progress[row.SubjectID]
+		p.TotalTopics = row.TotalTopics
+		p.NotStartedTopics = row.NotStarted
+		p.LearningTopics = row.Learning
+		p.ReviewedTopics = row.Reviewed
+		p.MasteredTopics = row.Mastered
+		p.LastMasteredAt = row.LastMasteredAt
+		if row.TotalTopics > 0 {
+			// aprender conta um terço do tópico, revisar dois terços e dominar o tópico inteiro
+			weighted := float64(row.Learning+2*row.Reviewed+3*row.Mastered) / 3
+			p.PercentMastered = percent(float64(row.Mastered), float64(row.TotalTopics))
+			p.PercentComplete = percent(weighted, float64(row.TotalTopics))
+		}
+	}
+	for _, row := range tasks {
+		p := progress[row.SubjectID]
+		p.TotalStudyTasks = row.TotalTasks
+		p.DoneStudyTasks = row.DoneTasks
+		if row.TotalTasks > 0 {
+			p.PercentTasksDone = percent(float64(row.DoneTasks), float64(row.TotalTasks))
+		}
+	}
+	for _, row := range resources {
+		p := progress[row.SubjectID]
+		p.TotalResources = row.Total
+		p.DoneResources = row.Done
+		p.PercentResources = percent(row.Ratio, 1)
+	}
+	return progress, nil
+}
+
+func percent(part, total float64) float64 {
+	return math.Round(part/total*10000) / 100
+}
+
+func (r *studySubjectRepository) CreateTerm(t *Term) error {
+	return r.db.Create(t).Error
+}
+
+func (r *studySubjectRepository) GetTerm(id string) (*Term, error) {
+	var t Term
+	if err := r.db.First(&t, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *studySubjectRepository) ListTerms(userID uuid.UUID) ([]*Term, error) {
+	var terms []*Term
+	if err := r.db.Where("user_id = ?", userID).Order("start_date DESC").Find(&terms).Error; err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *studySubjectRepository) UpdateTerm(t *Term) error {
+	return r.db.Save(t).Error
+}
+
+// DeleteTerm remove o período e deixa as matérias dele sem período.
+func (r *studySubjectRepository) DeleteTerm(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE study_subjects SET term_id = NULL WHERE term_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Term{}, "id = ?", id).Error
+	})
+}
+
+// ActiveTerm devolve o período que contém o dia; se houver sobreposição, vence o que começou por último.
+// O dia deve vir de util.LocalNow(), já que as datas do período são dias locais.
+func (r *studySubjectRepository) ActiveTerm(userID uuid.UUID, day time.Time) (*Term, error) {
+	var t Term
+	d := day.Format("2006-01-02")
+	err := r.db.Where("user_id = ? AND start_date <= ?::date AND end_date >= ?::date", userID, d, d).
+		Order("start_date DESC").
+		First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+type termSubjectRow struct {
+	SubjectID         uuid.UUID
+	TermID            uuid.UUID
+	Name              string
+	Archived          bool
+	StudyMinutes      int64
+	StudyTasks        int64
+	DoneStudyTasks    int64
+	Assessments       int64
+	GradedAssessments int64
+	Earned            float64
+	GradedWeight      float64
+}
+
+// GetTermSummaries agrega por matéria as tasks STUDY (dos tópicos e das avaliações) e as notas,
+// e soma tudo por período.
+func (r *studySubjectRepository) GetTermSummaries(termIDs []uuid.UUID) (map[uuid.UUID]*TermSummary, error) {
+	summaries := make(map[uuid.UUID]*TermSummary, len(termIDs))
+	if len(termIDs) == 0 {
+		return summaries, nil
+	}
+
+	var rows []termSubjectRow
+	err := r.db.Raw(`SELECT s.id AS subject_id, s.term_id, s.name, s.archived,
+			COALESCE(t.study_minutes, 0) AS study_minutes,
+			COALESCE(t.study_tasks, 0) AS study_tasks,
+			COALESCE(t.done_study_tasks, 0) AS done_study_tasks,
+			COALESCE(a.assessments, 0) AS assessments,
+			COALESCE(a.graded_assessments, 0) AS graded_assessments,
+			COALESCE(a.earned, 0) AS earned,
+			COALESCE(a.graded_weight, 0) AS graded_weight
+		FROM study_subjects s
+		LEFT JOIN LATERAL (
+			SELECT SUM(tk.logged_minutes) AS study_minutes,
+				COUNT(*) AS study_tasks,
+				COUNT(*) FILTER (WHERE tk.status = 'DONE') AS done_study_tasks
+			FROM tasks tk
+			WHERE tk.type = 'STUDY' AND (
+				tk.study_topic_id IN (SELECT id FROM study_topics WHERE subject_id = s.id)
+				OR tk.assessment_id IN (SELECT id FROM assessments WHERE subject_id = s.id))
+		) t ON true
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS assessments,
+				COUNT(*) FILTER (WHERE score IS NOT NULL) AS graded_assessments,
+				SUM(weight * score / max_score) FILTER (WHERE score IS NOT NULL) AS earned,
+				SUM(weight) FILTER (WHERE score IS NOT NULL) AS graded_weight
+			FROM assessments
+			WHERE subject_id = s.id
+		) a ON true
+		WHERE s.term_id IN ?
+		ORDER BY s.name`, termIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range termIDs {
+		summaries[id] = &TermSummary{SubjectSummaries: []SubjectSummary{}}
+	}
+	// a média do período é a média simples das matérias, já que cada uma usa sua própria escala de pesos
+	averages := make(map[uuid.UUID][]float64, len(termIDs))
+	for _, row := range rows {
+		sum := summaries[row.TermID]
+		sum.Subjects++
+		if row.Archived {
+			sum.ArchivedSubjects++
+		}
+		sum.StudyMinutes += row.StudyMinutes
+		sum.StudyTasks += row.StudyTasks
+		sum.DoneStudyTasks += row.DoneStudyTasks
+		sum.Assessments += row.Assessments
+		sum.GradedAssessments += row.GradedAssessments
+
+		subject := SubjectSummary{
+			SubjectID:         row.SubjectID,
+			Name:              row.Name,
+			Archived:          row.Archived,
+			StudyMinutes:      row.StudyMinutes,
+			StudyTasks:        row.StudyTasks,
+			DoneStudyTasks:    row.DoneStudyTasks,
+			Assessments:       row.Assessments,
+			GradedAssessments: row.GradedAssessments,
+		}
+		if row.GradedWeight > 0 {
+			avg := percent(row.Earned, row.GradedWeight)
+			subject.WeightedAverage = &avg
+			averages[row.TermID] = append(averages[row.TermID], avg)
+		}
+		sum.SubjectSummaries = append(sum.SubjectSummaries, subject)
+	}
+	for id, sum := range summaries {
+		if len(averages[id]) == 0 {
+			continue
+		}
+		var total float64
+		for _, avg := range averages[id] {
+			total += avg
+		}
+		avg := math.Round(total/float64(len(averages[id]))*100) / 100
+		sum.WeightedAverage = &avg
+	}
+	return summaries, nil
+}
@@ -14,8 +14,27 @@ func Routes(h *Handler) http.Handler {
 
 	r.Post("/", h.CreateStudySubject)
 	r.Get("/", h.ListStudySubjects)
+	r.Get("/{id}", h.GetStudySubject)
 	r.Put("/{id}", h.UpdateStudySubject)
+	r.Get("/{id}/deletion-preview", h.PreviewDeletion)
+	r.Put("/{id}/archive", h.SetArchived)
+	r.Put("/{id}/term", h.AssignTerm)
+	r.Put("/{id}/weekly-target", h.SetWeeklyTarget)
 	r.Delete("/{id}", h.DeleteStudySubject)
 
 	return r
 }
+
+func TermRoutes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Post("/", h.CreateTerm)
+	r.Get("/", h.ListTerms)
+	r.Get("/{id}", h.GetTerm)
+	r.Put("/{id}", h.UpdateTerm)
+	r.Delete("/{id}", h.DeleteTerm)
+
+	return r
+}
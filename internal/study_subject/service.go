@@ -3,24 +3,39 @@ package studysubject
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 	"github.com/sirupsen/logrus"
 )
 
 var (
 	ErrStudySubjectNotFound = errors.New("study subject not found")
 	ErrUnauthorized         = errors.New("unauthorized")
+
+	ErrStudySubjectHasChildren = errors.New("study subject has topics, assessments or study sessions")
+	ErrInvalidReassignTarget   = errors.New("invalid reassign target study subject")
 )
 
 type StudySubjectService interface {
 	CreateStudySubject(ctx context.Context, subj *StudySubject) (*StudySubject, error)
-	ListStudySubjectsByUser(ctx context.Context, userID string) ([]*StudySubject, error)
+	ListStudySubjectsByUser(ctx context.Context, userID string, opts ListOptions) ([]*StudySubject, error)
+	GetStudySubject(ctx context.Context, id string) (*StudySubject, error)
 	UpdateStudySubject(ctx context.Context, subj *StudySubject) (*StudySubject, error)
-	DeleteStudySubject(ctx context.Context, id string) error
+	DeleteStudySubject(ctx context.Context, id string, opts DeleteOptions) error
+	PreviewDeletion(ctx context.Context, id string) (*DeletionImpact, error)
+	SetArchived(ctx context.Context, id string, archived bool) (*StudySubject, error)
+	AssignTerm(ctx context.Context, id string, termID *string) (*StudySubject, error)
+	SetWeeklyTarget(ctx context.Context, id string, dto *WeeklyTargetDTO) (*StudySubject, error)
+	CreateTerm(ctx context.Context, dto *TermDTO) (*Term, error)
+	GetTerm(ctx context.Context, id string) (*Term, error)
+	ListTerms(ctx context.Context) ([]*Term, error)
+	UpdateTerm(ctx context.Context, id string, dto *TermDTO) (*Term, error)
+	DeleteTerm(ctx context.Context, id string) error
 }
 
 type studySubjectService struct {
@@ -47,9 +62,27 @@ func (s *studySubjectService) CreateStudySubject(ctx context.Context, subj *Stud
 
 	subj.UserID = uuid.MustParse(claims.UserID)
 	subj.ID = uuid.New()
+	subj.Archived = false
+	subj.ArchivedAt = nil
 	subj.CreatedAt = time.Now()
 	subj.UpdatedAt = time.Now()
 
+	// sem período informado, a matéria entra no período ativo para aparecer na listagem padrão
+	if subj.TermID != nil {
+		if _, err := s.getOwnedTerm(ctx, subj.TermID.String(), "create subject in"); err != nil {
+			return nil, err
+		}
+	} else {
+		active, err := s.repo.ActiveTerm(subj.UserID, util.LocalNow())
+		if err != nil {
+			log.WithError(err).Error("Error fetching active term")
+			return nil, err
+		}
+		if active != nil {
+			subj.TermID = &active.ID
+		}
+	}
+
 	if err := s.repo.Create(subj); err != nil {
 		log.WithError(err).Error("failed to create study subject")
 		return nil, err
@@ -57,7 +90,7 @@ func (s *studySubjectService) CreateStudySubject(ctx context.Context, subj *Stud
 	return subj, nil
 }
 
-func (s *studySubjectService) ListStudySubjectsByUser(ctx context.Context, userID string) ([]*StudySubject, error) {
+func (s *studySubjectService) ListStudySubjectsByUser(ctx context.Context, userID string, opts ListOptions) ([]*StudySubject, error) {
 	log := config.WithContext(ctx)
 
 	claims, err := auth.GetUserClaimsFromContext(ctx)
@@ -66,7 +99,7 @@ func (s *studySubjectService) ListStudySubjectsByUser(ctx context.Context, userI
 		return nil, ErrUnauthorized
 	}
 
-	if claims.UserID != userID {
+	if ownerID, err := uuid.Parse(userID); err != nil || !auth.IsOwner(claims.UserID, ownerID) {
 		log.WithFields(logrus.Fields{
 			"user_id_from_path":   userID,
 			"user_id_from_claims": claims.UserID,
@@ -74,14 +107,55 @@ func (s *studySubjectService) ListStudySubjectsByUser(ctx context.Context, userI
 		return nil, ErrUnauthorized
 	}
 
-	subjects, err := s.repo.ListByUser(userID)
+	filter, err := s.resolveFilter(ctx, uuid.MustParse(userID), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects, err := s.repo.ListByUser(userID, filter)
 	if err != nil {
 		log.WithError(err).Error("failed to list study subjects by user")
 		return nil, err
 	}
+
+	if err := s.attachProgress(ctx, subjects); err != nil {
+		return nil, err
+	}
 	return subjects, nil
 }
 
+func (s *studySubjectService) GetStudySubject(ctx context.Context, id string) (*StudySubject, error) {
+	subject, err := s.getOwnedSubject(ctx, id, "access")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachProgress(ctx, []*StudySubject{subject}); err != nil {
+		return nil, err
+	}
+	return subject, nil
+}
+
+func (s *studySubjectService) attachProgress(ctx context.Context, subjects []*StudySubject) error {
+	log := config.WithContext(ctx)
+
+	ids := make([]uuid.UUID, 0, len(subjects))
+	for _, subj := range subjects {
+		ids = append(ids, subj.ID)
+	}
+
+	progress, err := s.repo.GetProgress(ids)
+	if err != nil {
+		log.WithError(err).Error("Error computing study subject progress")
+		return err
+	}
+
+	for _, subj := range subjects {
+		subj.Progress = progress[subj.ID]
+	}
+	return nil
+}
+
 func (s *studySubjectService) UpdateStudySubject(ctx context.Context, subj *StudySubject) (*StudySubject, error) {
 	log := config.WithContext(ctx)
 
@@ -100,7 +174,7 @@ func (s *studySubjectService) UpdateStudySubject(ctx context.Context, subj *Stud
 		return nil, ErrStudySubjectNotFound
 	}
 
-	if existing.UserID.String() != claims.UserID {
+	if !auth.IsOwner(claims.UserID, existing.UserID) {
 		log.WithFields(logrus.Fields{
 			"subject_id": existing.ID,
 			"user_id":    claims.UserID,
@@ -123,35 +197,358 @@ func (s *studySubjectService) UpdateStudySubject(ctx context.Context, subj *Stud
 	return existing, nil
 }
 
-func (s *studySubjectService) DeleteStudySubject(ctx context.Context, id string) error {
+func (s *studySubjectService) DeleteStudySubject(ctx context.Context, id string, opts DeleteOptions) error {
+	log := config.WithContext(ctx)
+
+	subject, err := s.getOwnedSubject(ctx, id, "delete")
+	if err != nil {
+		return err
+	}
+
+	if opts.Strategy == util.DeleteReassign {
+		if opts.TargetSubjectID == "" || opts.TargetSubjectID == id {
+			return ErrInvalidReassignTarget
+		}
+		if _, err := s.getOwnedSubject(ctx, opts.TargetSubjectID, "reassign topics to"); err != nil {
+			if errors.Is(err, ErrStudySubjectNotFound) {
+				return ErrInvalidReassignTarget
+			}
+			return err
+		}
+	}
+
+	if err := s.repo.DeleteWithStrategy(subject.ID.String(), opts.Strategy, opts.TargetSubjectID); err != nil {
+		if errors.Is(err, ErrStudySubjectHasChildren) || errors.Is(err, util.ErrInvalidDeleteStrategy) {
+			log.WithError(err).WithField("subject_id", id).Warn("Study subject deletion rejected")
+			return err
+		}
+		log.WithError(err).Error("failed to delete study subject")
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		"subject_id": id,
+		"strategy":   opts.Strategy,
+	}).Info("Study subject deleted successfully")
+	return nil
+}
+
+func (s *studySubjectService) PreviewDeletion(ctx context.Context, id string) (*DeletionImpact, error) {
+	log := config.WithContext(ctx)
+
+	subject, err := s.getOwnedSubject(ctx, id, "preview deletion of")
+	if err != nil {
+		return nil, err
+	}
+
+	impact, err := s.repo.CountChildren(subject.ID.String())
+	if err != nil {
+		log.WithError(err).Error("Error counting study subject children")
+		return nil, err
+	}
+	return impact, nil
+}
+
+func (s *studySubjectService) getOwnedSubject(ctx context.Context, id string, action string) (*StudySubject, error) {
 	log := config.WithContext(ctx)
 
 	claims, err := auth.GetUserClaimsFromContext(ctx)
 	if err != nil {
-		log.WithError(err).Warn("Attempt to delete study subject without authentication")
-		return ErrUnauthorized
+		log.WithError(err).Warnf("Attempt to %s study subject without authentication", action)
+		return nil, ErrUnauthorized
 	}
 
 	subject, err := s.repo.GetByID(id)
 	if err != nil {
-		log.WithError(err).Error("Error fetching study subject for deletion")
-		return err
+		log.WithError(err).Error("Error fetching study subject")
+		return nil, err
 	}
 	if subject == nil {
-		return ErrStudySubjectNotFound
+		return nil, ErrStudySubjectNotFound
 	}
 
-	if subject.UserID.String() != claims.UserID {
+	if !auth.IsOwner(claims.UserID, subject.UserID) {
 		log.WithFields(logrus.Fields{
 			"subject_id": subject.ID,
 			"user_id":    claims.UserID,
-		}).Warn("User attempted to delete another user's study subject")
-		return ErrUnauthorized
+		}).Warnf("User attempted to %s another user's study subject", action)
+		return nil, ErrUnauthorized
 	}
+	return subject, nil
+}
 
-	if err := s.repo.Delete(id); err != nil {
-		log.WithError(err).Error("failed to delete study subject")
+// resolveFilter traduz o filtro ?term=. Sem período ativo, a listagem padrão mostra todas as
+// matérias não arquivadas, como antes de existirem períodos.
+func (s *studySubjectService) resolveFilter(ctx context.Context, userID uuid.UUID, opts ListOptions) (SubjectFilter, error) {
+	log := config.WithContext(ctx)
+	filter := SubjectFilter{IncludeArchived: opts.IncludeArchived}
+
+	switch opts.Term {
+	case termFilterAll:
+		return filter, nil
+	case termFilterNone:
+		filter.Unassigned = true
+		return filter, nil
+	case "", termFilterActive:
+		active, err := s.repo.ActiveTerm(userID, util.LocalNow())
+		if err != nil {
+			log.WithError(err).Error("Error fetching active term")
+			return filter, err
+		}
+		if active != nil {
+			filter.TermID = &active.ID
+		}
+		return filter, nil
+	default:
+		term, err := s.getOwnedTerm(ctx, opts.Term, "list subjects of")
+		if err != nil {
+			return filter, err
+		}
+		filter.TermID = &term.ID
+		return filter, nil
+	}
+}
+
+func (s *studySubjectService) SetArchived(ctx context.Context, id string, archived bool) (*StudySubject, error) {
+	log := config.WithContext(ctx)
+
+	subject, err := s.getOwnedSubject(ctx, id, "archive")
+	if err != nil {
+		return nil, err
+	}
+	if subject.Archived == archived {
+		return subject, nil
+	}
+
+	now := time.Now()
+	subject.Archived = archived
+	subject.ArchivedAt = nil
+	if archived {
+		subject.ArchivedAt = &now
+	}
+	subject.UpdatedAt = now
+
+	if err := s.repo.Update(subject); err != nil {
+		log.WithError(err).Error("Failed to update study subject archive flag")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"subject_id": subject.ID,
+		"archived":   archived,
+	}).Info("Study subject archive flag updated successfully")
+	return subject, nil
+}
+
+func (s *studySubjectService) SetWeeklyTarget(ctx context.Context, id string, dto *WeeklyTargetDTO) (*StudySubject, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	subject, err := s.getOwnedSubject(ctx, id, "set weekly target of")
+	if err != nil {
+		return nil, err
+	}
+
+	subject.WeeklyTarget = dto.WeeklyTargetMinutes
+	subject.UpdatedAt = time.Now()
+	if err := s.repo.Update(subject); err != nil {
+		log.WithError(err).Error("Failed to update study subject weekly target")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"subject_id":            subject.ID,
+		"weekly_target_minutes": subject.WeeklyTarget,
+	}).Info("Study subject weekly target updated successfully")
+	return subject, nil
+}
+
+// AssignTerm move a matéria para um período; termID nulo deixa a matéria sem período.
+func (s *studySubjectService) AssignTerm(ctx context.Context, id string, termID *string) (*StudySubject, error) {
+	log := config.WithContext(ctx)
+
+	subject, err := s.getOwnedSubject(ctx, id, "assign term to")
+	if err != nil {
+		return nil, err
+	}
+
+	subject.TermID = nil
+	if termID != nil {
+		term, err := s.getOwnedTerm(ctx, *termID, "assign subject to")
+		if err != nil {
+			return nil, err
+		}
+		subject.TermID = &term.ID
+	}
+	subject.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(subject); err != nil {
+		log.WithError(err).Error("Failed to assign term to study subject")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"subject_id": subject.ID,
+		"term_id":    subject.TermID,
+	}).Info("Study subject term updated successfully")
+	return subject, nil
+}
+
+func (s *studySubjectService) getOwnedTerm(ctx context.Context, id string, action string) (*Term, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("Attempt to %s term without authentication", action)
+		return nil, ErrUnauthorized
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrTermNotFound
+	}
+
+	term, err := s.repo.GetTerm(id)
+	if err != nil {
+		log.WithError(err).Error("Error fetching term")
+		return nil, err
+	}
+	if term == nil {
+		return nil, ErrTermNotFound
+	}
+
+	if !auth.IsOwner(claims.UserID, term.UserID) {
+		log.WithFields(logrus.Fields{
+			"term_id": term.ID,
+			"user_id": claims.UserID,
+		}).Warnf("User attempted to %s another user's term", action)
+		return nil, ErrUnauthorized
+	}
+	return term, nil
+}
+
+func (s *studySubjectService) CreateTerm(ctx context.Context, dto *TermDTO) (*Term, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Attempt to create term without authentication")
+		return nil, ErrUnauthorized
+	}
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	term := &Term{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(dto.Name),
+		StartDate: truncateDay(dto.StartDate),
+		EndDate:   truncateDay(dto.EndDate),
+		UserID:    uuid.MustParse(claims.UserID),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateTerm(term); err != nil {
+		log.WithError(err).Error("Failed to create term")
+		return nil, err
+	}
+
+	log.WithField("term_id", term.ID).Info("Term created successfully")
+	return term, nil
+}
+
+func (s *studySubjectService) GetTerm(ctx context.Context, id string) (*Term, error) {
+	term, err := s.getOwnedTerm(ctx, id, "access")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachSummaries(ctx, []*Term{term}); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+func (s *studySubjectService) ListTerms(ctx context.Context) ([]*Term, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Attempt to list terms without authentication")
+		return nil, ErrUnauthorized
+	}
+
+	terms, err := s.repo.ListTerms(uuid.MustParse(claims.UserID))
+	if err != nil {
+		log.WithError(err).Error("Failed to list terms")
+		return nil, err
+	}
+
+	if err := s.attachSummaries(ctx, terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (s *studySubjectService) UpdateTerm(ctx context.Context, id string, dto *TermDTO) (*Term, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	term, err := s.getOwnedTerm(ctx, id, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	term.Name = strings.TrimSpace(dto.Name)
+	term.StartDate = truncateDay(dto.StartDate)
+	term.EndDate = truncateDay(dto.EndDate)
+	term.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateTerm(term); err != nil {
+		log.WithError(err).Error("Failed to update term")
+		return nil, err
+	}
+
+	log.WithField("term_id", term.ID).Info("Term updated successfully")
+	return term, nil
+}
+
+func (s *studySubjectService) DeleteTerm(ctx context.Context, id string) error {
+	log := config.WithContext(ctx)
+
+	term, err := s.getOwnedTerm(ctx, id, "delete")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTerm(term.ID); err != nil {
+		log.WithError(err).Error("Failed to delete term")
+		return err
+	}
+
+	log.WithField("term_id", term.ID).Info("Term deleted successfully")
+	return nil
+}
+
+func (s *studySubjectService) attachSummaries(ctx context.Context, terms []*Term) error {
+	log := config.WithContext(ctx)
+
+	ids := make([]uuid.UUID, 0, len(terms))
+	for _, t := range terms {
+		ids = append(ids, t.ID)
+	}
+
+	summaries, err := s.repo.GetTermSummaries(ids)
+	if err != nil {
+		log.WithError(err).Error("Error computing term summaries")
 		return err
 	}
+
+	for _, t := range terms {
+		t.Summary = summaries[t.ID]
+	}
 	return nil
 }
@@ -0,0 +1,106 @@
+package studysubject
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTermNotFound = errors.New("term not found")
+	ErrInvalidTerm  = errors.New("term name is required and end date cannot be before start date")
+)
+
+const (
+	// valores especiais do filtro ?term= da listagem de matérias
+	termFilterActive = "active"
+	termFilterAll    = "all"
+	termFilterNone   = "none"
+)
+
+// Term agrupa as matérias de um semestre ou período letivo.
+type Term struct {
+	ID        uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	Name      string       `json:"name"`
+	StartDate time.Time    `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time    `gorm:"type:date;not null" json:"end_date"`
+	UserID    uuid.UUID    `gorm:"column:user_id;not null;index" json:"user_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Summary   *TermSummary `gorm:"-" json:"summary,omitempty"`
+}
+
+// IsActive indica se o dia está dentro do período, com as duas pontas inclusas.
+func (t *Term) IsActive(day time.Time) bool {
+	d := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return !d.Before(t.StartDate) && !d.After(t.EndDate)
+}
+
+type TermDTO struct {
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+func (dto *TermDTO) Validate() error {
+	if strings.TrimSpace(dto.Name) == "" || dto.StartDate.IsZero() || dto.EndDate.IsZero() {
+		return ErrInvalidTerm
+	}
+	if truncateDay(dto.EndDate).Before(truncateDay(dto.StartDate)) {
+		return ErrInvalidTerm
+	}
+	return nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ListOptions filtra a listagem de matérias. Term aceita o ID de um período ou
+// "active" (padrão), "all" e "none" para as matérias sem período.
+type ListOptions struct {
+	Term            string
+	IncludeArchived bool
+}
+
+// SubjectFilter é o filtro já resolvido que o repositório aplica.
+type SubjectFilter struct {
+	TermID          *uuid.UUID
+	Unassigned      bool
+	IncludeArchived bool
+}
+
+type AssignTermDTO struct {
+	TermID *string `json:"term_id"`
+}
+
+type ArchiveDTO struct {
+	Archived bool `json:"archived"`
+}
+
+// TermSummary resume tempo de estudo e notas das matérias do período.
+type TermSummary struct {
+	Subjects          int64            `json:"subjects"`
+	ArchivedSubjects  int64            `json:"archived_subjects"`
+	StudyMinutes      int64            `json:"study_minutes"`
+	StudyTasks        int64            `json:"study_tasks"`
+	DoneStudyTasks    int64            `json:"done_study_tasks"`
+	Assessments       int64            `json:"assessments"`
+	GradedAssessments int64            `json:"graded_assessments"`
+	WeightedAverage   *float64         `json:"weighted_average"`
+	SubjectSummaries  []SubjectSummary `json:"subject_summaries"`
+}
+
+type SubjectSummary struct {
+	SubjectID         uuid.UUID `json:"subject_id"`
+	Name              string    `json:"name"`
+	Archived          bool      `json:"archived"`
+	StudyMinutes      int64     `json:"study_minutes"`
+	StudyTasks        int64     `json:"study_tasks"`
+	DoneStudyTasks    int64     `json:"done_study_tasks"`
+	Assessments       int64     `json:"assessments"`
+	GradedAssessments int64     `json:"graded_assessments"`
+	WeightedAverage   *float64  `json:"weighted_average"`
+}
@@ -0,0 +1,18 @@
+package studytopic
+
+import "github.com/saulo-duarte/chronos-lambda/internal/util"
+
+type DeleteOptions struct {
+	Strategy      util.DeleteStrategy
+	TargetTopicID string
+}
+
+type DeletionImpact struct {
+	Tasks      int64 `json:"tasks"`
+	Flashcards int64 `json:"flashcards"`
+	Resources  int64 `json:"resources"`
+}
+
+func (i *DeletionImpact) HasChildren() bool {
+	return i.Tasks > 0 || i.Flashcards > 0 || i.Resources > 0
+}
@@ -8,15 +8,43 @@ import (
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 )
 
+// StudyTopic tem posição única por matéria. Como o repositório não roda migrações, o índice
+// precisa existir no banco:
+//
+//	CREATE UNIQUE INDEX IF NOT EXISTS idx_study_topics_subject_position
+//	    ON study_topics (subject_id, position);
+//
+// A checagem é imediata (não DEFERRABLE), por isso applyOrder reordena passando por
+// posições negativas.
 type StudyTopic struct {
 	ID             uuid.UUID                 `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
 	Name           string                    `json:"name"`
 	Description    string                    `json:"description"`
-	Position       int                       `gorm:"default:0" json:"position"`
+	Position       int                       `gorm:"not null;uniqueIndex:idx_study_topics_subject_position,priority:2" json:"position"`
 	UserID         uuid.UUID                 `gorm:"column:user_id;not null" json:"user_id"`
 	User           user.User                 `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-" gorm:"-"`
-	StudySubjectID uuid.UUID                 `gorm:"column:subject_id;not null" json:"subject_id"`
+	StudySubjectID uuid.UUID                 `gorm:"column:subject_id;not null;uniqueIndex:idx_study_topics_subject_position,priority:1" json:"subject_id"`
 	StudySubject   studysubject.StudySubject `gorm:"foreignKey:StudySubjectID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-" gorm:"-"`
+	Mastery        MasteryState              `gorm:"default:NOT_STARTED" json:"mastery"`
+	StartedAt      *time.Time                `json:"started_at"`
+	MasteredAt     *time.Time                `json:"mastered_at"`
 	CreatedAt      time.Time                 `json:"created_at"`
 	UpdatedAt      time.Time                 `json:"updated_at"`
+	Resources      *ResourceProgress         `gorm:"-" json:"resources,omitempty"`
+}
+
+// setMastery muda o estado de domínio mantendo as datas: StartedAt marca a primeira saída de
+// NOT_STARTED e MasteredAt só existe enquanto o tópico estiver MASTERED.
+func (t *StudyTopic) setMastery(state MasteryState, now time.Time) {
+	t.Mastery = state
+	if state != NOT_STARTED && t.StartedAt == nil {
+		t.StartedAt = &now
+	}
+	if state == MASTERED {
+		if t.MasteredAt == nil {
+			t.MasteredAt = &now
+		}
+	} else {
+		t.MasteredAt = nil
+	}
 }
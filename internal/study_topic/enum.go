@@ -0,0 +1,26 @@
+package studytopic
+
+type MasteryState string
+
+const (
+	NOT_STARTED MasteryState = "NOT_STARTED"
+	LEARNING    MasteryState = "LEARNING"
+	REVIEWED    MasteryState = "REVIEWED"
+	MASTERED    MasteryState = "MASTERED"
+)
+
+var AllMasteryStates = []MasteryState{
+	NOT_STARTED,
+	LEARNING,
+	REVIEWED,
+	MASTERED,
+}
+
+func (s MasteryState) IsValid() bool {
+	for _, v := range AllMasteryStates {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
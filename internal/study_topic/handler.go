@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 )
 
 type Handler struct {
@@ -57,7 +58,9 @@ func (h *Handler) CreateStudyTopic(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, studysubject.ErrStudySubjectNotFound):
 			http.Error(w, "study subject not found", http.StatusNotFound)
-		case err.Error() == "já existe um tópico com esta posição neste assunto":
+		case errors.Is(err, ErrInvalidPosition):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrPositionConflict):
 			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			log.WithError(err).Error("Error creating study topic")
@@ -126,6 +129,50 @@ func (h *Handler) ListStudyTopics(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type reorderTopicsPayload struct {
+	TopicIDs []string `json:"topic_ids"`
+}
+
+func (h *Handler) ReorderStudyTopics(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	subjectID := chi.URLParam(r, "studySubjectId")
+	if _, err := uuid.Parse(subjectID); err != nil {
+		http.Error(w, "invalid study subject id", http.StatusBadRequest)
+		return
+	}
+
+	var payload reorderTopicsPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	topics, err := h.service.ReorderTopics(r.Context(), subjectID, payload.TopicIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, studysubject.ErrStudySubjectNotFound):
+			http.Error(w, "study subject not found", http.StatusNotFound)
+		case errors.Is(err, ErrInvalidTopicOrder):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrPositionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.WithError(err).Error("Error reordering study topics")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":  len(topics),
+		"topics": topics,
+	})
+}
+
 func (h *Handler) UpdateStudyTopic(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 
@@ -151,6 +198,10 @@ func (h *Handler) UpdateStudyTopic(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, ErrStudyTopicNotFound):
 			http.Error(w, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, ErrInvalidPosition), errors.Is(err, ErrInvalidMastery):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrPositionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			log.WithError(err).Error("Error updating study topic")
 			http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -171,12 +222,27 @@ func (h *Handler) DeleteStudyTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteStudyTopic(r.Context(), topicID); err != nil {
+	strategy, err := util.ParseDeleteStrategy(r.URL.Query().Get("strategy"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := DeleteOptions{
+		Strategy:      strategy,
+		TargetTopicID: r.URL.Query().Get("target"),
+	}
+
+	if err := h.service.DeleteStudyTopic(r.Context(), topicID, opts); err != nil {
 		switch {
 		case errors.Is(err, ErrUnauthorized):
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 		case errors.Is(err, ErrStudyTopicNotFound):
 			http.Error(w, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, ErrInvalidReassignTarget):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrStudyTopicHasChildren):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			log.WithError(err).Error("Error deleting study topic")
 			http.Error(w, "internal server error", http.StatusInternalServerError)
@@ -188,3 +254,322 @@ func (h *Handler) DeleteStudyTopic(w http.ResponseWriter, r *http.Request) {
 		"message": "study topic deleted successfully",
 	})
 }
+
+func (h *Handler) PreviewDeletion(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	topicID := chi.URLParam(r, "id")
+	if topicID == "" {
+		log.Warn("Study topic ID not provided")
+		http.Error(w, "study topic id required", http.StatusBadRequest)
+		return
+	}
+
+	impact, err := h.service.PreviewDeletion(r.Context(), topicID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound):
+			http.Error(w, "study topic not found", http.StatusNotFound)
+		default:
+			log.WithError(err).Error("Error previewing study topic deletion")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, impact)
+}
+
+func (h *Handler) RecordReview(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	topicID := chi.URLParam(r, "id")
+	if topicID == "" {
+		log.Warn("Study topic ID not provided")
+		http.Error(w, "study topic id required", http.StatusBadRequest)
+		return
+	}
+
+	var payload RecordReviewDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if payload.Grade == nil {
+		http.Error(w, "grade is required", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.service.RecordReview(r.Context(), topicID, *payload.Grade)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound):
+			http.Error(w, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, util.ErrInvalidRecallGrade):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.WithError(err).Error("Error recording study topic review")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, review)
+}
+
+type updateMasteryPayload struct {
+	Mastery MasteryState `json:"mastery"`
+}
+
+func (h *Handler) UpdateMastery(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	topicID := chi.URLParam(r, "id")
+	if topicID == "" {
+		log.Warn("Study topic ID not provided")
+		http.Error(w, "study topic id required", http.StatusBadRequest)
+		return
+	}
+
+	var payload updateMasteryPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	topic, err := h.service.UpdateMastery(r.Context(), topicID, payload.Mastery)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrStudyTopicNotFound):
+			http.Error(w, "study topic not found", http.StatusNotFound)
+		case errors.Is(err, ErrInvalidMastery):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.WithError(err).Error("Error updating study topic mastery")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, topic)
+}
+
+func (h *Handler) AddPrerequisite(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload AddPrerequisiteDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	edge, err := h.service.AddPrerequisite(r.Context(), chi.URLParam(r, "id"), payload.PrerequisiteID)
+	if err != nil {
+		writePrerequisiteError(w, r, err, "Error adding topic prerequisite")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, edge)
+}
+
+func (h *Handler) RemovePrerequisite(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemovePrerequisite(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "prerequisiteId")); err != nil {
+		writePrerequisiteError(w, r, err, "Error removing topic prerequisite")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "prerequisite removed successfully",
+	})
+}
+
+func (h *Handler) ListPrerequisites(w http.ResponseWriter, r *http.Request) {
+	topics, err := h.service.ListPrerequisites(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writePrerequisiteError(w, r, err, "Error listing topic prerequisites")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":         len(topics),
+		"prerequisites": topics,
+	})
+}
+
+func (h *Handler) GetLearningPath(w http.ResponseWriter, r *http.Request) {
+	path, err := h.service.GetLearningPath(r.Context(), chi.URLParam(r, "studySubjectId"))
+	if err != nil {
+		writePrerequisiteError(w, r, err, "Error building learning path")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, path)
+}
+
+func writePrerequisiteError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrStudyTopicNotFound):
+		http.Error(w, "study topic not found", http.StatusNotFound)
+	case errors.Is(err, studysubject.ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrPrerequisiteNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidPrerequisite):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrPrerequisiteCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ImportSyllabus(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ImportSyllabusDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preview := r.URL.Query().Get("preview") == "true"
+	result, err := h.service.ImportSyllabus(r.Context(), &payload, preview)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrInvalidSyllabus):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.WithError(err).Error("Error importing syllabus")
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	status := http.StatusCreated
+	if preview {
+		status = http.StatusOK
+	}
+	config.JSON(w, status, result)
+}
+
+func (h *Handler) CreateResource(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ResourceDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.CreateResource(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeResourceError(w, r, err, "Error creating resource")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, res)
+}
+
+func (h *Handler) ListResources(w http.ResponseWriter, r *http.Request) {
+	resources, err := h.service.ListResources(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeResourceError(w, r, err, "Error listing resources")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":     len(resources),
+		"resources": resources,
+	})
+}
+
+func (h *Handler) GetResource(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetResource(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeResourceError(w, r, err, "Error fetching resource")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) UpdateResource(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ResourceDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.UpdateResource(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeResourceError(w, r, err, "Error updating resource")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) UpdateResourceProgress(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload ResourceProgressDTO
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Invalid request body")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.UpdateResourceProgress(r.Context(), chi.URLParam(r, "id"), &payload)
+	if err != nil {
+		writeResourceError(w, r, err, "Error updating resource progress")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) DeleteResource(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteResource(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeResourceError(w, r, err, "Error deleting resource")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "resource deleted successfully",
+	})
+}
+
+func writeResourceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrStudyTopicNotFound):
+		http.Error(w, "study topic not found", http.StatusNotFound)
+	case errors.Is(err, ErrResourceNotFound):
+		http.Error(w, "resource not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidResource), errors.Is(err, ErrInvalidResourceKind), errors.Is(err, ErrInvalidProgress):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,172 @@
+package studytopic
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPrerequisiteCycle    = errors.New("prerequisite would create a cycle")
+	ErrInvalidPrerequisite  = errors.New("a topic cannot be its own prerequisite")
+	ErrPrerequisiteNotFound = errors.New("prerequisite not found")
+)
+
+// TopicPrerequisite indica que TopicID só deve ser estudado depois que PrerequisiteID for dominado.
+// Os dois tópicos podem ser de matérias diferentes do mesmo usuário.
+type TopicPrerequisite struct {
+	TopicID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"topic_id"`
+	PrerequisiteID uuid.UUID `gorm:"type:uuid;primaryKey;column:prerequisite_id" json:"prerequisite_id"`
+	UserID         uuid.UUID `gorm:"column:user_id;not null;index" json:"user_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type AddPrerequisiteDTO struct {
+	PrerequisiteID string `json:"prerequisite_id"`
+}
+
+type LearningStep struct {
+	Topic              *StudyTopic `json:"topic"`
+	Prerequisites      []uuid.UUID `json:"prerequisites"`
+	UnmetPrerequisites []uuid.UUID `json:"unmet_prerequisites"`
+	Ready              bool        `json:"ready"`
+	External           bool        `json:"external"`
+}
+
+type LearningPath struct {
+	SubjectID uuid.UUID      `json:"subject_id"`
+	Steps     []LearningStep `json:"steps"`
+}
+
+// createsPrerequisiteCycle verifica se topicID já é alcançável a partir de prerequisiteID,
+// caso em que a nova aresta fecharia um ciclo.
+func createsPrerequisiteCycle(edges []TopicPrerequisite, topicID, prerequisiteID uuid.UUID) bool {
+	requires := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		requires[e.TopicID] = append(requires[e.TopicID], e.PrerequisiteID)
+	}
+
+	visited := map[uuid.UUID]bool{}
+	stack := []uuid.UUID{prerequisiteID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == topicID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, requires[id]...)
+	}
+	return false
+}
+
+// OrderByPrerequisites ordena os tópicos para que pré-requisitos venham antes (Kahn),
+// desempatando pela ordem recebida. Arestas para tópicos fora da lista são ignoradas.
+func OrderByPrerequisites(topics []*StudyTopic, edges []TopicPrerequisite) ([]*StudyTopic, error) {
+	index := make(map[uuid.UUID]int, len(topics))
+	for i, t := range topics {
+		index[t.ID] = i
+	}
+
+	indegree := make([]int, len(topics))
+	unlocks := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		i, ok := index[e.TopicID]
+		if _, known := index[e.PrerequisiteID]; !ok || !known {
+			continue
+		}
+		indegree[i]++
+		unlocks[e.PrerequisiteID] = append(unlocks[e.PrerequisiteID], e.TopicID)
+	}
+
+	// a lista costuma ser pequena, então basta procurar o primeiro tópico liberado a cada passo
+	placed := make([]bool, len(topics))
+	ordered := make([]*StudyTopic, 0, len(topics))
+	for len(ordered) < len(topics) {
+		next := -1
+		for i := range topics {
+			if !placed[i] && indegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, ErrPrerequisiteCycle
+		}
+		placed[next] = true
+		ordered = append(ordered, topics[next])
+		for _, id := range unlocks[topics[next].ID] {
+			indegree[index[id]]--
+		}
+	}
+	return ordered, nil
+}
+
+// buildLearningPath monta o caminho da matéria incluindo, antes de quem depende deles,
+// os pré-requisitos (diretos ou indiretos) que estão em outras matérias.
+func buildLearningPath(subjectID uuid.UUID, topics []*StudyTopic, edges []TopicPrerequisite) (*LearningPath, error) {
+	byID := make(map[uuid.UUID]*StudyTopic, len(topics))
+	for _, t := range topics {
+		byID[t.ID] = t
+	}
+	requires := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range edges {
+		if byID[e.TopicID] == nil || byID[e.PrerequisiteID] == nil {
+			continue
+		}
+		requires[e.TopicID] = append(requires[e.TopicID], e.PrerequisiteID)
+	}
+
+	var own []*StudyTopic
+	included := map[uuid.UUID]bool{}
+	var stack []uuid.UUID
+	for _, t := range topics {
+		if t.StudySubjectID == subjectID {
+			own = append(own, t)
+			included[t.ID] = true
+			stack = append(stack, t.ID)
+		}
+	}
+
+	var external []*StudyTopic
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, req := range requires[id] {
+			if included[req] {
+				continue
+			}
+			included[req] = true
+			external = append(external, byID[req])
+			stack = append(stack, req)
+		}
+	}
+
+	ordered, err := OrderByPrerequisites(append(own, external...), edges)
+	if err != nil {
+		return nil, err
+	}
+
+	path := &LearningPath{SubjectID: subjectID, Steps: make([]LearningStep, 0, len(ordered))}
+	for _, t := range ordered {
+		step := LearningStep{
+			Topic:              t,
+			Prerequisites:      []uuid.UUID{},
+			UnmetPrerequisites: []uuid.UUID{},
+			External:           t.StudySubjectID != subjectID,
+		}
+		for _, req := range requires[t.ID] {
+			step.Prerequisites = append(step.Prerequisites, req)
+			if byID[req].Mastery != MASTERED {
+				step.UnmetPrerequisites = append(step.UnmetPrerequisites, req)
+			}
+		}
+		step.Ready = t.Mastery != MASTERED && len(step.UnmetPrerequisites) == 0
+		path.Steps = append(path.Steps, step)
+	}
+	return path, nil
+}
@@ -2,8 +2,16 @@ package studytopic
 
 import (
 	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StudyTopicRepository interface {
@@ -12,6 +20,28 @@ type StudyTopicRepository interface {
 	ListBySubject(studySubjectID string) ([]*StudyTopic, error)
 	Update(t *StudyTopic) error
 	Delete(id string) error
+	CountChildren(id string) (*DeletionImpact, error)
+	DeleteWithStrategy(id string, strategy util.DeleteStrategy, targetID string) error
+	GetReview(topicID uuid.UUID) (*TopicReview, error)
+	SaveReview(review *TopicReview) error
+	ListDueReviews(userID uuid.UUID, before time.Time) ([]*DueReview, error)
+	CreateAtPosition(t *StudyTopic) error
+	MoveToPosition(t *StudyTopic) error
+	Reorder(subjectID uuid.UUID, topicIDs []uuid.UUID) error
+	ListByUser(userID uuid.UUID) ([]*StudyTopic, error)
+	AddPrerequisite(p *TopicPrerequisite) error
+	RemovePrerequisite(topicID, prerequisiteID uuid.UUID) error
+	ListPrerequisitesByUser(userID uuid.UUID) ([]TopicPrerequisite, error)
+	ListPrerequisiteTopics(topicID uuid.UUID) ([]*StudyTopic, error)
+	CreateSubjectWithTopics(subject *studysubject.StudySubject, topics []*StudyTopic) error
+	CreateResource(res *Resource) error
+	GetResource(id string) (*Resource, error)
+	ListResources(topicID uuid.UUID) ([]*Resource, error)
+	ListResourcesByTopics(topicIDs []uuid.UUID) ([]*Resource, error)
+	UpdateResource(res *Resource) error
+	SaveResourceProgress(res *Resource, startedTopic *StudyTopic) error
+	DeleteResource(id uuid.UUID) error
+	GetResourceProgress(topicIDs []uuid.UUID) (map[uuid.UUID]*ResourceProgress, error)
 }
 
 type studyTopicRepository struct {
@@ -39,7 +69,7 @@ func (r *studyTopicRepository) GetByID(id string) (*StudyTopic, error) {
 
 func (r *studyTopicRepository) ListBySubject(studySubjectID string) ([]*StudyTopic, error) {
 	var topics []*StudyTopic
-	if err := r.db.Where("subject_id = ?", studySubjectID).Find(&topics).Error; err != nil {
+	if err := r.db.Where("subject_id = ?", studySubjectID).Order("position, created_at").Find(&topics).Error; err != nil {
 		return nil, err
 	}
 	return topics, nil
@@ -49,6 +79,460 @@ func (r *studyTopicRepository) Update(t *StudyTopic) error {
 	return r.db.Save(t).Error
 }
 
+// lockSubject serializa as alterações de ordem dos tópicos de uma mesma matéria.
+func lockSubject(tx *gorm.DB, subjectID uuid.UUID) error {
+	return tx.Exec("SELECT 1 FROM study_subjects WHERE id = ? FOR UPDATE", subjectID).Error
+}
+
+func orderedTopicIDs(tx *gorm.DB, subjectID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := tx.Model(&StudyTopic{}).
+		Where("subject_id = ?", subjectID).
+		Order("position, created_at").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// applyOrder grava as posições 1..n na ordem de ids. As posições passam primeiro
+// por valores negativos para não violar o índice único (subject_id, position).
+func applyOrder(tx *gorm.DB, subjectID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Exec(applyOrderSQL(len(ids)), orderArgs(subjectID, ids)...).Error; err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE study_topics SET position = -position WHERE subject_id = ? AND position < 0", subjectID).Error
+}
+
+// applyOrderSQL monta um placeholder por id: passar o slice inteiro faria o gorm gerar
+// ARRAY[($1,$2)], uma linha só, que o Postgres não converte para uuid[].
+func applyOrderSQL(n int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?::uuid,", n), ",")
+	return `UPDATE study_topics AS st SET position = -o.pos
+		FROM unnest(ARRAY[` + placeholders + `]) WITH ORDINALITY AS o(id, pos)
+		WHERE st.id = o.id AND st.subject_id = ?`
+}
+
+func orderArgs(subjectID uuid.UUID, ids []uuid.UUID) []interface{} {
+	args := make([]interface{}, 0, len(ids)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return append(args, subjectID)
+}
+
+// insertAt coloca id na posição (1-based) indicada; posições fora do intervalo vão para o fim.
+func insertAt(ids []uuid.UUID, id uuid.UUID, position int) ([]uuid.UUID, int) {
+	idx := position - 1
+	if idx < 0 || idx > len(ids) {
+		idx = len(ids)
+	}
+	ids = append(ids, uuid.Nil)
+	copy(ids[idx+1:], ids[idx:])
+	ids[idx] = id
+	return ids, idx + 1
+}
+
+func removeID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	out := ids[:0]
+	for _, existing := range ids {
+		if existing != id {
+			out = append(out, existing)
+		}
+	}
+	return out
+}
+
+// CreateAtPosition insere o tópico na posição pedida deslocando os vizinhos;
+// posição 0 (ou além do fim) acrescenta o tópico ao final.
+func (r *studyTopicRepository) CreateAtPosition(t *StudyTopic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockSubject(tx, t.StudySubjectID); err != nil {
+			return err
+		}
+		ids, err := orderedTopicIDs(tx, t.StudySubjectID)
+		if err != nil {
+			return err
+		}
+
+		ids, position := insertAt(ids, t.ID, t.Position)
+		// applyOrder usa -1..-len(ids) no primeiro passo; a posição provisória fica fora
+		// desse intervalo para não colidir com o vizinho que termina em último
+		t.Position = -(len(ids) + 1)
+		if err := tx.Create(t).Error; err != nil {
+			return err
+		}
+		if err := applyOrder(tx, t.StudySubjectID, ids); err != nil {
+			return err
+		}
+		t.Position = position
+		return nil
+	})
+}
+
+// MoveToPosition salva o tópico e o move para t.Position, deslocando os vizinhos.
+func (r *studyTopicRepository) MoveToPosition(t *StudyTopic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockSubject(tx, t.StudySubjectID); err != nil {
+			return err
+		}
+		ids, err := orderedTopicIDs(tx, t.StudySubjectID)
+		if err != nil {
+			return err
+		}
+
+		ids, position := insertAt(removeID(ids, t.ID), t.ID, t.Position)
+		if err := tx.Model(t).Select("name", "description", "mastery", "started_at", "mastered_at", "updated_at").Updates(t).Error; err != nil {
+			return err
+		}
+		if err := applyOrder(tx, t.StudySubjectID, ids); err != nil {
+			return err
+		}
+		t.Position = position
+		return nil
+	})
+}
+
+// Reorder aplica a ordem completa enviada; topicIDs precisa conter exatamente os tópicos da matéria.
+func (r *studyTopicRepository) Reorder(subjectID uuid.UUID, topicIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockSubject(tx, subjectID); err != nil {
+			return err
+		}
+		current, err := orderedTopicIDs(tx, subjectID)
+		if err != nil {
+			return err
+		}
+
+		if len(current) != len(topicIDs) {
+			return ErrInvalidTopicOrder
+		}
+		expected := make(map[uuid.UUID]bool, len(current))
+		for _, id := range current {
+			expected[id] = true
+		}
+		for _, id := range topicIDs {
+			if !expected[id] {
+				return ErrInvalidTopicOrder
+			}
+			delete(expected, id)
+		}
+
+		return applyOrder(tx, subjectID, topicIDs)
+	})
+}
+
 func (r *studyTopicRepository) Delete(id string) error {
 	return r.db.Delete(&StudyTopic{}, "id = ?", id).Error
 }
+
+func (r *studyTopicRepository) CountChildren(id string) (*DeletionImpact, error) {
+	return countChildren(r.db, id)
+}
+
+func countChildren(db *gorm.DB, id string) (*DeletionImpact, error) {
+	var impact DeletionImpact
+	if err := db.Table("tasks").Where("study_topic_id = ?", id).Count(&impact.Tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("flashcards").Where("topic_id = ?", id).Count(&impact.Flashcards).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("resources").Where("topic_id = ?", id).Count(&impact.Resources).Error; err != nil {
+		return nil, err
+	}
+	return &impact, nil
+}
+
+// DeleteWithStrategy remove o tópico tratando as tasks vinculadas numa única transação.
+func (r *studyTopicRepository) DeleteWithStrategy(id string, strategy util.DeleteStrategy, targetID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var topic StudyTopic
+		if err := tx.Select("id", "subject_id").First(&topic, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := lockSubject(tx, topic.StudySubjectID); err != nil {
+			return err
+		}
+
+		switch strategy {
+		case util.DeleteCascade:
+			if err := tx.Exec(`DELETE FROM task_dependencies
+				WHERE task_id IN (SELECT id FROM tasks WHERE study_topic_id = ?)
+				OR depends_on_id IN (SELECT id FROM tasks WHERE study_topic_id = ?)`, id, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM tasks WHERE study_topic_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM flashcards WHERE topic_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM resources WHERE topic_id = ?", id).Error; err != nil {
+				return err
+			}
+		case util.DeleteReassign:
+			if err := tx.Exec("UPDATE tasks SET study_topic_id = ? WHERE study_topic_id = ?", targetID, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE flashcards SET topic_id = ? WHERE topic_id = ?", targetID, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE resources SET topic_id = ? WHERE topic_id = ?", targetID, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`UPDATE study_sessions
+				SET topic_id = ?, subject_id = (SELECT subject_id FROM study_topics WHERE id = ?)
+				WHERE topic_id = ?`, targetID, targetID, id).Error; err != nil {
+				return err
+			}
+		case util.DeleteDetach:
+			// cartões e materiais não existem fora de um tópico, então são removidos junto com ele
+			if err := tx.Exec("UPDATE tasks SET study_topic_id = NULL WHERE study_topic_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM flashcards WHERE topic_id = ?", id).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM resources WHERE topic_id = ?", id).Error; err != nil {
+				return err
+			}
+		default:
+			impact, err := countChildren(tx, id)
+			if err != nil {
+				return err
+			}
+			if impact.HasChildren() {
+				return ErrStudyTopicHasChildren
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM topic_reviews WHERE topic_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("topic_id = ? OR prerequisite_id = ?", id, id).Delete(&TopicPrerequisite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM assessment_topics WHERE topic_id = ?", id).Error; err != nil {
+			return err
+		}
+		// sessões registradas continuam contando para a matéria, só perdem o tópico
+		if err := tx.Exec("UPDATE study_sessions SET topic_id = NULL WHERE topic_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&StudyTopic{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// fecha o buraco deixado na ordem dos tópicos restantes
+		ids, err := orderedTopicIDs(tx, topic.StudySubjectID)
+		if err != nil {
+			return err
+		}
+		return applyOrder(tx, topic.StudySubjectID, ids)
+	})
+}
+
+func (r *studyTopicRepository) GetReview(topicID uuid.UUID) (*TopicReview, error) {
+	var review TopicReview
+	if err := r.db.First(&review, "topic_id = ?", topicID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *studyTopicRepository) SaveReview(review *TopicReview) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(review).Error
+}
+
+// ListDueReviews lista as revisões do usuário com data anterior a before, junto com seus tópicos.
+func (r *studyTopicRepository) ListDueReviews(userID uuid.UUID, before time.Time) ([]*DueReview, error) {
+	var reviews []*TopicReview
+	if err := r.db.Where("user_id = ? AND next_review_at < ?", userID, before).
+		Order("next_review_at").
+		Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	due := make([]*DueReview, 0, len(reviews))
+	if len(reviews) == 0 {
+		return due, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.TopicID)
+	}
+
+	var topics []*StudyTopic
+	if err := r.db.Where("id IN ?", ids).Find(&topics).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*StudyTopic, len(topics))
+	for _, t := range topics {
+		byID[t.ID] = t
+	}
+
+	for _, review := range reviews {
+		if topic, ok := byID[review.TopicID]; ok {
+			due = append(due, &DueReview{Topic: topic, Review: review})
+		}
+	}
+	return due, nil
+}
+
+// ListByUser ordena por matéria e posição para que a ordem de desempate do caminho seja estável.
+func (r *studyTopicRepository) ListByUser(userID uuid.UUID) ([]*StudyTopic, error) {
+	var topics []*StudyTopic
+	if err := r.db.Where("user_id = ?", userID).Order("subject_id, position, created_at").Find(&topics).Error; err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+func (r *studyTopicRepository) AddPrerequisite(p *TopicPrerequisite) error {
+	return r.db.Create(p).Error
+}
+
+func (r *studyTopicRepository) RemovePrerequisite(topicID, prerequisiteID uuid.UUID) error {
+	result := r.db.Where("topic_id = ? AND prerequisite_id = ?", topicID, prerequisiteID).Delete(&TopicPrerequisite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPrerequisiteNotFound
+	}
+	return nil
+}
+
+func (r *studyTopicRepository) ListPrerequisitesByUser(userID uuid.UUID) ([]TopicPrerequisite, error) {
+	var edges []TopicPrerequisite
+	if err := r.db.Where("user_id = ?", userID).Find(&edges).Error; err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// ListPrerequisiteTopics devolve os pré-requisitos diretos do tópico.
+func (r *studyTopicRepository) ListPrerequisiteTopics(topicID uuid.UUID) ([]*StudyTopic, error) {
+	var topics []*StudyTopic
+	err := r.db.Joins("JOIN topic_prerequisites tp ON tp.prerequisite_id = study_topics.id").
+		Where("tp.topic_id = ?", topicID).
+		Order("study_topics.subject_id, study_topics.position").
+		Find(&topics).Error
+	return topics, err
+}
+
+// CreateSubjectWithTopics grava a matéria importada e seus tópicos numa única transação.
+func (r *studyTopicRepository) CreateSubjectWithTopics(subject *studysubject.StudySubject, topics []*StudyTopic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(subject).Error; err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&topics).Error
+	})
+}
+
+func (r *studyTopicRepository) CreateResource(res *Resource) error {
+	return r.db.Create(res).Error
+}
+
+func (r *studyTopicRepository) GetResource(id string) (*Resource, error) {
+	var res Resource
+	if err := r.db.First(&res, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *studyTopicRepository) ListResources(topicID uuid.UUID) ([]*Resource, error) {
+	var resources []*Resource
+	if err := r.db.Where("topic_id = ?", topicID).Order("created_at").Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (r *studyTopicRepository) ListResourcesByTopics(topicIDs []uuid.UUID) ([]*Resource, error) {
+	var resources []*Resource
+	if len(topicIDs) == 0 {
+		return resources, nil
+	}
+	if err := r.db.Where("topic_id IN ?", topicIDs).Order("created_at").Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (r *studyTopicRepository) UpdateResource(res *Resource) error {
+	return r.db.Save(res).Error
+}
+
+// SaveResourceProgress grava o progresso do material e, se informado, o tópico que acabou
+// de sair de NOT_STARTED por causa dele.
+func (r *studyTopicRepository) SaveResourceProgress(res *Resource, startedTopic *StudyTopic) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(res).Error; err != nil {
+			return err
+		}
+		if startedTopic == nil {
+			return nil
+		}
+		return tx.Model(&StudyTopic{}).Where("id = ?", startedTopic.ID).Updates(map[string]interface{}{
+			"mastery":    startedTopic.Mastery,
+			"started_at": startedTopic.StartedAt,
+			"updated_at": startedTopic.UpdatedAt,
+		}).Error
+	})
+}
+
+func (r *studyTopicRepository) DeleteResource(id uuid.UUID) error {
+	return r.db.Delete(&Resource{}, "id = ?", id).Error
+}
+
+type resourceProgressRow struct {
+	TopicID   uuid.UUID
+	Total     int64
+	Completed int64
+	Ratio     float64
+}
+
+// GetResourceProgress agrega os materiais de cada tópico sem carregar os registros.
+func (r *studyTopicRepository) GetResourceProgress(topicIDs []uuid.UUID) (map[uuid.UUID]*ResourceProgress, error) {
+	progress := make(map[uuid.UUID]*ResourceProgress, len(topicIDs))
+	if len(topicIDs) == 0 {
+		return progress, nil
+	}
+
+	var rows []resourceProgressRow
+	err := r.db.Table("resources").
+		Select(`topic_id,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE completed_units >= total_units) AS completed,
+			COALESCE(AVG(LEAST(completed_units::float / NULLIF(total_units, 0), 1)), 0) AS ratio`).
+		Where("topic_id IN ?", topicIDs).
+		Group("topic_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		progress[row.TopicID] = &ResourceProgress{
+			Total:     row.Total,
+			Completed: row.Completed,
+			Percent:   math.Round(row.Ratio*10000) / 100,
+		}
+	}
+	return progress, nil
+}
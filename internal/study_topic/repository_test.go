@@ -0,0 +1,63 @@
+package studytopic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB gera o SQL do dialeto Postgres sem abrir conexão.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=chronos"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("open dry-run db: %v", err)
+	}
+	return db
+}
+
+func TestApplyOrderBindsOneParameterPerTopic(t *testing.T) {
+	db := dryRunDB(t)
+	subjectID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	var stmts []*gorm.Statement
+	if err := db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		stmts = append(stmts, tx.Statement)
+	}); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	if err := applyOrder(db, subjectID, ids); err != nil {
+		t.Fatalf("applyOrder: %v", err)
+	}
+	if len(stmts) != 2 {
+		t.Fatalf("applyOrder ran %d statements, want 2", len(stmts))
+	}
+	stmt := stmts[0]
+	sql := stmt.SQL.String()
+
+	if want := "unnest(ARRAY[$1::uuid,$2::uuid,$3::uuid])"; !strings.Contains(sql, want) {
+		t.Fatalf("SQL should contain %q, got:\n%s", want, sql)
+	}
+	if strings.Contains(sql, "($1") {
+		t.Fatalf("ids rendered as a row value:\n%s", sql)
+	}
+	if !strings.Contains(sql, "st.subject_id = $4") {
+		t.Fatalf("subject should be the last parameter:\n%s", sql)
+	}
+	if len(stmt.Vars) != len(ids)+1 {
+		t.Fatalf("got %d vars, want %d", len(stmt.Vars), len(ids)+1)
+	}
+	for i, id := range ids {
+		if stmt.Vars[i] != id {
+			t.Errorf("var %d = %v, want %v", i, stmt.Vars[i], id)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package studytopic
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ResourceKind string
+
+const (
+	RESOURCE_BOOK    ResourceKind = "BOOK"
+	RESOURCE_CHAPTER ResourceKind = "CHAPTER"
+	RESOURCE_VIDEO   ResourceKind = "VIDEO"
+	RESOURCE_ARTICLE ResourceKind = "ARTICLE"
+	RESOURCE_COURSE  ResourceKind = "COURSE"
+	RESOURCE_LINK    ResourceKind = "LINK"
+	RESOURCE_OTHER   ResourceKind = "OTHER"
+)
+
+var AllResourceKinds = []ResourceKind{
+	RESOURCE_BOOK,
+	RESOURCE_CHAPTER,
+	RESOURCE_VIDEO,
+	RESOURCE_ARTICLE,
+	RESOURCE_COURSE,
+	RESOURCE_LINK,
+	RESOURCE_OTHER,
+}
+
+func (k ResourceKind) IsValid() bool {
+	for _, v := range AllResourceKinds {
+		if k == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceUnit é a unidade de TotalUnits e CompletedUnits.
+type ResourceUnit string
+
+const (
+	UNIT_PAGES   ResourceUnit = "PAGES"
+	UNIT_MINUTES ResourceUnit = "MINUTES"
+	UNIT_LESSONS ResourceUnit = "LESSONS"
+	UNIT_ITEMS   ResourceUnit = "ITEMS"
+)
+
+func (u ResourceUnit) IsValid() bool {
+	switch u {
+	case UNIT_PAGES, UNIT_MINUTES, UNIT_LESSONS, UNIT_ITEMS:
+		return true
+	}
+	return false
+}
+
+// defaultUnit escolhe a unidade mais natural para cada tipo de material.
+func defaultUnit(kind ResourceKind) ResourceUnit {
+	switch kind {
+	case RESOURCE_BOOK, RESOURCE_CHAPTER, RESOURCE_ARTICLE:
+		return UNIT_PAGES
+	case RESOURCE_VIDEO:
+		return UNIT_MINUTES
+	case RESOURCE_COURSE:
+		return UNIT_LESSONS
+	}
+	return UNIT_ITEMS
+}
+
+const maxResourceUnits = 100000
+
+var (
+	ErrResourceNotFound    = errors.New("resource not found")
+	ErrInvalidResource     = errors.New("resource title is required, url must be http(s) and total units must be between 1 and 100000")
+	ErrInvalidResourceKind = errors.New("invalid resource kind or unit")
+	ErrInvalidProgress     = errors.New("send either completed_units or delta, and completed units must stay between 0 and total units")
+)
+
+// Resource é um material de estudo do tópico (livro, capítulo, vídeo, link...) com o
+// quanto dele já foi percorrido.
+type Resource struct {
+	ID             uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	TopicID        uuid.UUID    `gorm:"type:uuid;not null;index" json:"topic_id"`
+	UserID         uuid.UUID    `gorm:"column:user_id;not null" json:"user_id"`
+	Kind           ResourceKind `json:"kind"`
+	Title          string       `json:"title"`
+	URL            string       `json:"url"`
+	Reference      string       `json:"reference"`
+	Unit           ResourceUnit `json:"unit"`
+	TotalUnits     int          `gorm:"not null" json:"total_units"`
+	CompletedUnits int          `gorm:"not null;default:0" json:"completed_units"`
+	CompletedAt    *time.Time   `json:"completed_at"`
+	Percent        float64      `gorm:"-" json:"percent"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// ResourceProgress resume os materiais de um tópico. Percent é a média do percentual de
+// cada material, já que as unidades de materiais diferentes não se somam.
+type ResourceProgress struct {
+	Total     int64   `json:"total"`
+	Completed int64   `json:"completed"`
+	Percent   float64 `json:"percent"`
+}
+
+type ResourceDTO struct {
+	Kind       ResourceKind `json:"kind"`
+	Title      string       `json:"title"`
+	URL        string       `json:"url"`
+	Reference  string       `json:"reference"`
+	Unit       ResourceUnit `json:"unit"`
+	TotalUnits int          `json:"total_units"`
+}
+
+func (dto *ResourceDTO) Validate() error {
+	if dto.Kind == "" {
+		dto.Kind = RESOURCE_OTHER
+	}
+	if dto.Unit == "" {
+		dto.Unit = defaultUnit(dto.Kind)
+	}
+	if !dto.Kind.IsValid() || !dto.Unit.IsValid() {
+		return ErrInvalidResourceKind
+	}
+	// links e materiais sem tamanho definido contam como uma unidade: feito ou não feito
+	if dto.TotalUnits == 0 {
+		dto.TotalUnits = 1
+	}
+	if strings.TrimSpace(dto.Title) == "" || dto.TotalUnits < 0 || dto.TotalUnits > maxResourceUnits {
+		return ErrInvalidResource
+	}
+	if dto.URL != "" {
+		u, err := url.Parse(strings.TrimSpace(dto.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidResource
+		}
+	}
+	return nil
+}
+
+// ResourceProgressDTO aceita o total concluído ou um incremento, nunca os dois.
+type ResourceProgressDTO struct {
+	CompletedUnits *int `json:"completed_units"`
+	Delta          *int `json:"delta"`
+}
+
+func (dto *ResourceProgressDTO) Validate() error {
+	if (dto.CompletedUnits == nil) == (dto.Delta == nil) {
+		return ErrInvalidProgress
+	}
+	return nil
+}
+
+// setProgress grava as unidades concluídas, limitadas ao total, e marca CompletedAt
+// enquanto o material estiver terminado.
+func (r *Resource) setProgress(units int, now time.Time) {
+	r.CompletedUnits = min(max(units, 0), r.TotalUnits)
+	if r.CompletedUnits == r.TotalUnits {
+		if r.CompletedAt == nil {
+			r.CompletedAt = &now
+		}
+	} else {
+		r.CompletedAt = nil
+	}
+	r.refreshPercent()
+}
+
+func (r *Resource) refreshPercent() {
+	r.Percent = 0
+	if r.TotalUnits > 0 {
+		r.Percent = math.Round(float64(r.CompletedUnits)/float64(r.TotalUnits)*10000) / 100
+	}
+}
@@ -0,0 +1,29 @@
+package studytopic
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+// TopicReview guarda o estado de repetição espaçada de um tópico.
+type TopicReview struct {
+	TopicID uuid.UUID `gorm:"type:uuid;primaryKey" json:"topic_id"`
+	UserID  uuid.UUID `gorm:"column:user_id;not null" json:"user_id"`
+	util.SRSState
+	LastGrade      int       `json:"last_grade"`
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+	NextReviewAt   time.Time `gorm:"type:date;index" json:"next_review_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type DueReview struct {
+	Topic  *StudyTopic  `json:"topic"`
+	Review *TopicReview `json:"review"`
+}
+
+type RecordReviewDTO struct {
+	Grade *int `json:"grade"`
+}
@@ -15,8 +15,30 @@ func Routes(h *Handler) http.Handler {
 	r.Post("/", h.CreateStudyTopic)
 	r.Get("/{id}", h.ListStudyTopics)
 	r.Put("/{id}", h.UpdateStudyTopic)
+	r.Get("/{id}/deletion-preview", h.PreviewDeletion)
+	r.Post("/{id}/reviews", h.RecordReview)
+	r.Put("/{id}/mastery", h.UpdateMastery)
+	r.Get("/{id}/prerequisites", h.ListPrerequisites)
+	r.Post("/{id}/prerequisites", h.AddPrerequisite)
+	r.Delete("/{id}/prerequisites/{prerequisiteId}", h.RemovePrerequisite)
+	r.Get("/{id}/resources", h.ListResources)
+	r.Post("/{id}/resources", h.CreateResource)
 	r.Delete("/{id}", h.DeleteStudyTopic)
 	r.Get("/{id}", h.GetStudyTopic)
 
 	return r
 }
+
+// ResourceRoutes expõe os materiais pelo próprio ID, montado em /resources.
+func ResourceRoutes(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(auth.AuthMiddleware)
+
+	r.Get("/{id}", h.GetResource)
+	r.Put("/{id}", h.UpdateResource)
+	r.Put("/{id}/progress", h.UpdateResourceProgress)
+	r.Delete("/{id}", h.DeleteResource)
+
+	return r
+}
@@ -3,19 +3,31 @@ package studytopic
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 var (
 	ErrStudyTopicNotFound   = errors.New("study topic not found")
 	ErrStudySubjectNotFound = studysubject.ErrStudySubjectNotFound
 	ErrUnauthorized         = errors.New("unauthorized")
+
+	ErrStudyTopicHasChildren = errors.New("study topic has tasks, flashcards or resources")
+	ErrInvalidReassignTarget = errors.New("invalid reassign target study topic")
+
+	ErrInvalidPosition   = errors.New("topic position cannot be negative")
+	ErrInvalidMastery    = errors.New("invalid mastery state")
+	ErrInvalidTopicOrder = errors.New("topic order must list every topic of the subject exactly once")
+	ErrPositionConflict  = errors.New("topic order changed concurrently, reload and try again")
 )
 
 type StudyTopicService interface {
@@ -23,7 +35,22 @@ type StudyTopicService interface {
 	GetStudyTopicByID(ctx context.Context, id string) (*StudyTopic, error)
 	ListStudyTopicsBySubject(ctx context.Context, studySubjectID string) ([]*StudyTopic, error)
 	UpdateStudyTopic(ctx context.Context, topic *StudyTopic) (*StudyTopic, error)
-	DeleteStudyTopic(ctx context.Context, id string) error
+	DeleteStudyTopic(ctx context.Context, id string, opts DeleteOptions) error
+	PreviewDeletion(ctx context.Context, id string) (*DeletionImpact, error)
+	RecordReview(ctx context.Context, id string, grade int) (*TopicReview, error)
+	ReorderTopics(ctx context.Context, studySubjectID string, topicIDs []string) ([]*StudyTopic, error)
+	UpdateMastery(ctx context.Context, id string, state MasteryState) (*StudyTopic, error)
+	AddPrerequisite(ctx context.Context, topicID, prerequisiteID string) (*TopicPrerequisite, error)
+	RemovePrerequisite(ctx context.Context, topicID, prerequisiteID string) error
+	ListPrerequisites(ctx context.Context, topicID string) ([]*StudyTopic, error)
+	GetLearningPath(ctx context.Context, studySubjectID string) (*LearningPath, error)
+	ImportSyllabus(ctx context.Context, dto *ImportSyllabusDTO, preview bool) (*SyllabusImport, error)
+	CreateResource(ctx context.Context, topicID string, dto *ResourceDTO) (*Resource, error)
+	ListResources(ctx context.Context, topicID string) ([]*Resource, error)
+	GetResource(ctx context.Context, id string) (*Resource, error)
+	UpdateResource(ctx context.Context, id string, dto *ResourceDTO) (*Resource, error)
+	UpdateResourceProgress(ctx context.Context, id string, dto *ResourceProgressDTO) (*Resource, error)
+	DeleteResource(ctx context.Context, id string) error
 }
 
 type studyTopicService struct {
@@ -57,7 +84,7 @@ func (s *studyTopicService) CreateStudyTopic(ctx context.Context, topic *StudyTo
 	if subject == nil {
 		return nil, ErrStudySubjectNotFound
 	}
-	if subject.UserID.String() != claims.UserID {
+	if !auth.IsOwner(claims.UserID, subject.UserID) {
 		log.WithFields(logrus.Fields{
 			"subject_id": subject.ID,
 			"user_id":    claims.UserID,
@@ -65,18 +92,20 @@ func (s *studyTopicService) CreateStudyTopic(ctx context.Context, topic *StudyTo
 		return nil, ErrUnauthorized
 	}
 
-	if topic.Position != 0 {
-		if err := s.validateUniquePosition(topic.Position, topic.StudySubjectID.String(), claims.UserID, ""); err != nil {
-			return nil, err
-		}
+	if topic.Position < 0 {
+		return nil, ErrInvalidPosition
 	}
 
 	topic.ID = uuid.New()
 	topic.UserID = uuid.MustParse(claims.UserID)
+	topic.Mastery = NOT_STARTED
 	topic.CreatedAt = time.Now()
 	topic.UpdatedAt = time.Now()
 
-	if err := s.repo.Create(topic); err != nil {
+	if err := s.repo.CreateAtPosition(topic); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrPositionConflict
+		}
 		log.WithError(err).Error("Failed to create study topic")
 		return nil, err
 	}
@@ -108,7 +137,7 @@ func (s *studyTopicService) GetStudyTopicByID(ctx context.Context, id string) (*
 		return nil, ErrStudyTopicNotFound
 	}
 
-	if topic.UserID.String() != claims.UserID {
+	if !auth.IsOwner(claims.UserID, topic.UserID) {
 		log.WithFields(logrus.Fields{
 			"topic_id": topic.ID,
 			"user_id":  claims.UserID,
@@ -116,6 +145,10 @@ func (s *studyTopicService) GetStudyTopicByID(ctx context.Context, id string) (*
 		return nil, ErrUnauthorized
 	}
 
+	if err := s.attachResourceProgress([]*StudyTopic{topic}); err != nil {
+		log.WithError(err).Error("Error fetching resource progress for study topic")
+		return nil, err
+	}
 	return topic, nil
 }
 
@@ -136,7 +169,7 @@ func (s *studyTopicService) ListStudyTopicsBySubject(ctx context.Context, studyS
 	if subject == nil {
 		return nil, ErrStudySubjectNotFound
 	}
-	if subject.UserID.String() != claims.UserID {
+	if !auth.IsOwner(claims.UserID, subject.UserID) {
 		log.WithFields(logrus.Fields{
 			"subject_id": studySubjectID,
 			"user_id":    claims.UserID,
@@ -149,6 +182,10 @@ func (s *studyTopicService) ListStudyTopicsBySubject(ctx context.Context, studyS
 		log.WithError(err).Error("Error listing study topics by subject")
 		return nil, err
 	}
+	if err := s.attachResourceProgress(topics); err != nil {
+		log.WithError(err).Error("Error fetching resource progress for study topics")
+		return nil, err
+	}
 
 	log.WithFields(logrus.Fields{
 		"subject_id": studySubjectID,
@@ -182,7 +219,7 @@ func (s *studyTopicService) UpdateStudyTopic(ctx context.Context, topic *StudyTo
 		return nil, ErrStudyTopicNotFound
 	}
 
-	if existing.UserID.String() != claims.UserID {
+	if !auth.IsOwner(claims.UserID, existing.UserID) {
 		log.WithFields(logrus.Fields{
 			"topic_id": existing.ID,
 			"user_id":  claims.UserID,
@@ -190,18 +227,31 @@ func (s *studyTopicService) UpdateStudyTopic(ctx context.Context, topic *StudyTo
 		return nil, ErrUnauthorized
 	}
 
-	if topic.Position != existing.Position {
-		if err := s.validateUniquePosition(topic.Position, existing.StudySubjectID.String(), claims.UserID, existing.ID.String()); err != nil {
-			return nil, err
-		}
+	if topic.Position < 0 {
+		return nil, ErrInvalidPosition
+	}
+	if topic.Mastery != "" && !topic.Mastery.IsValid() {
+		return nil, ErrInvalidMastery
 	}
 
 	existing.Name = topic.Name
 	existing.Description = topic.Description
-	existing.Position = topic.Position
 	existing.UpdatedAt = time.Now()
+	if topic.Mastery != "" && topic.Mastery != existing.Mastery {
+		existing.setMastery(topic.Mastery, existing.UpdatedAt)
+	}
 
-	if err := s.repo.Update(existing); err != nil {
+	// posição 0 mantém o tópico onde está
+	if topic.Position != 0 && topic.Position != existing.Position {
+		existing.Position = topic.Position
+		err = s.repo.MoveToPosition(existing)
+	} else {
+		err = s.repo.Update(existing)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrPositionConflict
+		}
 		log.WithError(err).Error("Failed to update study topic")
 		return nil, err
 	}
@@ -214,59 +264,604 @@ func (s *studyTopicService) UpdateStudyTopic(ctx context.Context, topic *StudyTo
 	return existing, nil
 }
 
-func (s *studyTopicService) DeleteStudyTopic(ctx context.Context, id string) error {
+func (s *studyTopicService) DeleteStudyTopic(ctx context.Context, id string, opts DeleteOptions) error {
 	log := config.WithContext(ctx)
 
-	claims, err := auth.GetUserClaimsFromContext(ctx)
-	if err != nil {
-		log.WithError(err).Warn("Attempt to delete study topic without authentication")
-		return ErrUnauthorized
-	}
-
-	topic, err := s.repo.GetByID(id)
+	topic, err := s.GetStudyTopicByID(ctx, id)
 	if err != nil {
-		log.WithError(err).Error("Error fetching study topic for deletion")
 		return err
 	}
-	if topic == nil {
-		return ErrStudyTopicNotFound
-	}
 
-	if topic.UserID.String() != claims.UserID {
-		log.WithFields(logrus.Fields{
-			"topic_id": topic.ID,
-			"user_id":  claims.UserID,
-		}).Warn("User attempted to delete another user's study topic")
-		return ErrUnauthorized
+	if opts.Strategy == util.DeleteReassign {
+		if opts.TargetTopicID == "" || opts.TargetTopicID == id {
+			return ErrInvalidReassignTarget
+		}
+		if _, err := s.GetStudyTopicByID(ctx, opts.TargetTopicID); err != nil {
+			if errors.Is(err, ErrStudyTopicNotFound) {
+				return ErrInvalidReassignTarget
+			}
+			return err
+		}
 	}
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.DeleteWithStrategy(topic.ID.String(), opts.Strategy, opts.TargetTopicID); err != nil {
+		if errors.Is(err, ErrStudyTopicHasChildren) {
+			log.WithField("topic_id", id).Warn("Study topic deletion blocked by existing tasks")
+			return err
+		}
 		log.WithError(err).Error("Failed to delete study topic")
 		return err
 	}
 
 	log.WithFields(logrus.Fields{
 		"topic_id": id,
-		"user_id":  claims.UserID,
+		"user_id":  topic.UserID,
+		"strategy": opts.Strategy,
 	}).Info("Study topic deleted successfully")
 
 	return nil
 }
 
-func (s *studyTopicService) validateUniquePosition(position int, studySubjectID string, userID string, excludeID string) error {
+func (s *studyTopicService) PreviewDeletion(ctx context.Context, id string) (*DeletionImpact, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	impact, err := s.repo.CountChildren(topic.ID.String())
+	if err != nil {
+		log.WithError(err).Error("Error counting study topic children")
+		return nil, err
+	}
+	return impact, nil
+}
+
+// ReorderTopics aplica de uma vez a ordem completa dos tópicos da matéria.
+func (s *studyTopicService) ReorderTopics(ctx context.Context, studySubjectID string, topicIDs []string) ([]*StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	subjectID, err := uuid.Parse(studySubjectID)
+	if err != nil {
+		return nil, ErrStudySubjectNotFound
+	}
+	// a listagem já valida autenticação e posse da matéria
+	if _, err := s.ListStudyTopicsBySubject(ctx, studySubjectID); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(topicIDs))
+	for _, raw := range topicIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, ErrInvalidTopicOrder
+		}
+		ids = append(ids, id)
+	}
+
+	if err := s.repo.Reorder(subjectID, ids); err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTopicOrder):
+			return nil, err
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return nil, ErrPositionConflict
+		}
+		log.WithError(err).Error("Failed to reorder study topics")
+		return nil, err
+	}
+
 	topics, err := s.repo.ListBySubject(studySubjectID)
+	if err != nil {
+		log.WithError(err).Error("Error listing study topics after reorder")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"subject_id": studySubjectID,
+		"count":      len(topics),
+	}).Info("Study topics reordered successfully")
+	return topics, nil
+}
+
+// RecordReview aplica a nota de recordação ao estado SM-2 do tópico e agenda a próxima revisão.
+func (s *studyTopicService) RecordReview(ctx context.Context, id string, grade int) (*TopicReview, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.repo.GetReview(topic.ID)
+	if err != nil {
+		log.WithError(err).Error("Error fetching study topic review")
+		return nil, err
+	}
+
+	now := time.Now()
+	if review == nil {
+		review = &TopicReview{
+			TopicID:   topic.ID,
+			UserID:    topic.UserID,
+			SRSState:  util.NewSRSState(),
+			CreatedAt: now,
+		}
+	}
+
+	state, err := review.SRSState.Review(grade)
+	if err != nil {
+		return nil, err
+	}
+
+	review.SRSState = state
+	review.LastGrade = grade
+	review.LastReviewedAt = now
+	// a próxima revisão cai num dia local, o mesmo que ListDueReviews usa de corte
+	review.NextReviewAt = state.NextReviewDate(util.LocalNow())
+	review.UpdatedAt = now
+
+	if err := s.repo.SaveReview(review); err != nil {
+		log.WithError(err).Error("Failed to save study topic review")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id":       topic.ID,
+		"grade":          grade,
+		"interval_days":  state.IntervalDays,
+		"next_review_at": review.NextReviewAt,
+	}).Info("Study topic review recorded successfully")
+
+	return review, nil
+}
+
+func (s *studyTopicService) UpdateMastery(ctx context.Context, id string, state MasteryState) (*StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	if !state.IsValid() {
+		return nil, ErrInvalidMastery
+	}
+
+	topic, err := s.GetStudyTopicByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if topic.Mastery == state {
+		return topic, nil
+	}
+
+	now := time.Now()
+	previous := topic.Mastery
+	topic.setMastery(state, now)
+	topic.UpdatedAt = now
+
+	if err := s.repo.Update(topic); err != nil {
+		log.WithError(err).Error("Failed to update study topic mastery")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id": topic.ID,
+		"from":     previous,
+		"to":       state,
+	}).Info("Study topic mastery updated successfully")
+	return topic, nil
+}
+
+func (s *studyTopicService) AddPrerequisite(ctx context.Context, topicID, prerequisiteID string) (*TopicPrerequisite, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(prerequisiteID); err != nil {
+		return nil, ErrStudyTopicNotFound
+	}
+	prerequisite, err := s.GetStudyTopicByID(ctx, prerequisiteID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.ID == prerequisite.ID {
+		return nil, ErrInvalidPrerequisite
+	}
+
+	edges, err := s.repo.ListPrerequisitesByUser(topic.UserID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list topic prerequisites")
+		return nil, err
+	}
+	for _, e := range edges {
+		if e.TopicID == topic.ID && e.PrerequisiteID == prerequisite.ID {
+			return &e, nil
+		}
+	}
+	if createsPrerequisiteCycle(edges, topic.ID, prerequisite.ID) {
+		log.WithFields(logrus.Fields{
+			"topic_id":        topic.ID,
+			"prerequisite_id": prerequisite.ID,
+		}).Warn("Prerequisite rejected because it would create a cycle")
+		return nil, ErrPrerequisiteCycle
+	}
+
+	edge := &TopicPrerequisite{
+		TopicID:        topic.ID,
+		PrerequisiteID: prerequisite.ID,
+		UserID:         topic.UserID,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.repo.AddPrerequisite(edge); err != nil {
+		log.WithError(err).Error("Failed to add topic prerequisite")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id":        topic.ID,
+		"prerequisite_id": prerequisite.ID,
+	}).Info("Topic prerequisite added successfully")
+	return edge, nil
+}
+
+func (s *studyTopicService) RemovePrerequisite(ctx context.Context, topicID, prerequisiteID string) error {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
 	if err != nil {
 		return err
 	}
+	pid, err := uuid.Parse(prerequisiteID)
+	if err != nil {
+		return ErrPrerequisiteNotFound
+	}
 
-	for _, topic := range topics {
-		if topic.Position == position && topic.UserID.String() == userID {
-			if excludeID != "" && topic.ID.String() == excludeID {
-				continue
-			}
-			return errors.New("já existe um tópico com esta posição neste assunto")
+	if err := s.repo.RemovePrerequisite(topic.ID, pid); err != nil {
+		if !errors.Is(err, ErrPrerequisiteNotFound) {
+			log.WithError(err).Error("Failed to remove topic prerequisite")
+		}
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		"topic_id":        topic.ID,
+		"prerequisite_id": pid,
+	}).Info("Topic prerequisite removed successfully")
+	return nil
+}
+
+func (s *studyTopicService) ListPrerequisites(ctx context.Context, topicID string) ([]*StudyTopic, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.repo.ListPrerequisiteTopics(topic.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list topic prerequisites")
+		return nil, err
+	}
+	return topics, nil
+}
+
+func (s *studyTopicService) GetLearningPath(ctx context.Context, studySubjectID string) (*LearningPath, error) {
+	log := config.WithContext(ctx)
+
+	subjectID, err := uuid.Parse(studySubjectID)
+	if err != nil {
+		return nil, ErrStudySubjectNotFound
+	}
+	// a listagem já valida autenticação e posse da matéria
+	if _, err := s.ListStudyTopicsBySubject(ctx, studySubjectID); err != nil {
+		return nil, err
+	}
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	userID := uuid.MustParse(claims.UserID)
+
+	topics, err := s.repo.ListByUser(userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list study topics for learning path")
+		return nil, err
+	}
+	edges, err := s.repo.ListPrerequisitesByUser(userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list topic prerequisites for learning path")
+		return nil, err
+	}
+
+	path, err := buildLearningPath(subjectID, topics, edges)
+	if err != nil {
+		log.WithError(err).WithField("subject_id", studySubjectID).Error("Topic prerequisites contain a cycle")
+		return nil, err
+	}
+	return path, nil
+}
+
+// ImportSyllabus cria a matéria e seus tópicos a partir de um esboço. No preview nada é gravado.
+func (s *studyTopicService) ImportSyllabus(ctx context.Context, dto *ImportSyllabusDTO, preview bool) (*SyllabusImport, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Attempt to import syllabus without authentication")
+		return nil, ErrUnauthorized
+	}
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	title, notes, topics, err := parseSyllabus(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(dto.Name)
+	if name == "" {
+		name = title
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: subject name is required when the outline has no title", ErrInvalidSyllabus)
+	}
+	description := dto.Description
+	if description == "" {
+		description = notes
+	}
+
+	now := time.Now()
+	userID := uuid.MustParse(claims.UserID)
+	subject := &studysubject.StudySubject{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: description,
+		UserID:      userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for i, t := range topics {
+		t.ID = uuid.New()
+		t.StudySubjectID = subject.ID
+		t.UserID = userID
+		t.Position = i + 1
+		t.Mastery = NOT_STARTED
+		t.CreatedAt = now
+		t.UpdatedAt = now
+	}
+
+	active, err := s.subjectRepo.ActiveTerm(userID, util.LocalNow())
+	if err != nil {
+		log.WithError(err).Error("Error fetching active term for syllabus import")
+		return nil, err
+	}
+	if active != nil {
+		subject.TermID = &active.ID
+	}
+
+	result := &SyllabusImport{Subject: subject, Topics: topics, Preview: preview}
+	if preview {
+		return result, nil
+	}
+
+	if err := s.repo.CreateSubjectWithTopics(subject, topics); err != nil {
+		log.WithError(err).Error("Failed to import syllabus")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"subject_id": subject.ID,
+		"user_id":    userID,
+		"format":     dto.Format,
+		"topics":     len(topics),
+	}).Info("Syllabus imported successfully")
+	return result, nil
+}
+
+func (s *studyTopicService) attachResourceProgress(topics []*StudyTopic) error {
+	ids := make([]uuid.UUID, 0, len(topics))
+	for _, t := range topics {
+		ids = append(ids, t.ID)
+	}
+	progress, err := s.repo.GetResourceProgress(ids)
+	if err != nil {
+		return err
+	}
+	for _, t := range topics {
+		t.Resources = progress[t.ID]
+	}
+	return nil
+}
+
+func (s *studyTopicService) getOwnedResource(ctx context.Context, id, action string) (*Resource, error) {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("Attempt to %s resource without authentication", action)
+		return nil, ErrUnauthorized
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrResourceNotFound
+	}
+
+	res, err := s.repo.GetResource(id)
+	if err != nil {
+		log.WithError(err).Error("Error fetching resource by ID")
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrResourceNotFound
+	}
+	if !auth.IsOwner(claims.UserID, res.UserID) {
+		log.WithFields(logrus.Fields{
+			"resource_id": res.ID,
+			"user_id":     claims.UserID,
+		}).Warnf("User attempted to %s another user's resource", action)
+		return nil, ErrUnauthorized
+	}
+	res.refreshPercent()
+	return res, nil
+}
+
+func applyResource(res *Resource, dto *ResourceDTO, now time.Time) {
+	res.Kind = dto.Kind
+	res.Title = strings.TrimSpace(dto.Title)
+	res.URL = strings.TrimSpace(dto.URL)
+	res.Reference = strings.TrimSpace(dto.Reference)
+	res.Unit = dto.Unit
+	res.TotalUnits = dto.TotalUnits
+	// mudar o total pode terminar ou reabrir o material
+	res.setProgress(res.CompletedUnits, now)
+	res.UpdatedAt = now
+}
+
+func (s *studyTopicService) CreateResource(ctx context.Context, topicID string, dto *ResourceDTO) (*Resource, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res := &Resource{
+		ID:        uuid.New(),
+		TopicID:   topic.ID,
+		UserID:    topic.UserID,
+		CreatedAt: now,
+	}
+	applyResource(res, dto, now)
+
+	if err := s.repo.CreateResource(res); err != nil {
+		log.WithError(err).Error("Failed to create resource")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"resource_id": res.ID,
+		"topic_id":    topic.ID,
+	}).Info("Resource created successfully")
+	return res, nil
+}
+
+func (s *studyTopicService) ListResources(ctx context.Context, topicID string) ([]*Resource, error) {
+	log := config.WithContext(ctx)
+
+	topic, err := s.GetStudyTopicByID(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := s.repo.ListResources(topic.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list resources")
+		return nil, err
+	}
+	for _, res := range resources {
+		res.refreshPercent()
+	}
+	return resources, nil
+}
+
+func (s *studyTopicService) GetResource(ctx context.Context, id string) (*Resource, error) {
+	return s.getOwnedResource(ctx, id, "access")
+}
+
+func (s *studyTopicService) UpdateResource(ctx context.Context, id string, dto *ResourceDTO) (*Resource, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	res, err := s.getOwnedResource(ctx, id, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	applyResource(res, dto, time.Now())
+	if err := s.repo.UpdateResource(res); err != nil {
+		log.WithError(err).Error("Failed to update resource")
+		return nil, err
+	}
+
+	log.WithField("resource_id", res.ID).Info("Resource updated successfully")
+	return res, nil
+}
+
+// UpdateResourceProgress registra o avanço no material. O primeiro avanço num tópico ainda
+// não iniciado o move para LEARNING; dominar o tópico continua sendo decisão do usuário.
+func (s *studyTopicService) UpdateResourceProgress(ctx context.Context, id string, dto *ResourceProgressDTO) (*Resource, error) {
+	log := config.WithContext(ctx)
+
+	if err := dto.Validate(); err != nil {
+		return nil, err
+	}
+	res, err := s.getOwnedResource(ctx, id, "update progress of")
+	if err != nil {
+		return nil, err
+	}
+
+	units := res.CompletedUnits
+	if dto.CompletedUnits != nil {
+		if *dto.CompletedUnits < 0 || *dto.CompletedUnits > res.TotalUnits {
+			return nil, ErrInvalidProgress
 		}
+		units = *dto.CompletedUnits
+	} else {
+		units += *dto.Delta
+	}
+
+	now := time.Now()
+	previous := res.CompletedUnits
+	res.setProgress(units, now)
+	res.UpdatedAt = now
+
+	var started *StudyTopic
+	if res.CompletedUnits > 0 {
+		topic, err := s.repo.GetByID(res.TopicID.String())
+		if err != nil {
+			log.WithError(err).Error("Error fetching study topic for resource progress")
+			return nil, err
+		}
+		if topic != nil && topic.Mastery == NOT_STARTED {
+			topic.setMastery(LEARNING, now)
+			topic.UpdatedAt = now
+			started = topic
+		}
+	}
+
+	if err := s.repo.SaveResourceProgress(res, started); err != nil {
+		log.WithError(err).Error("Failed to update resource progress")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"resource_id":   res.ID,
+		"from":          previous,
+		"to":            res.CompletedUnits,
+		"topic_started": started != nil,
+	}).Info("Resource progress updated successfully")
+	return res, nil
+}
+
+func (s *studyTopicService) DeleteResource(ctx context.Context, id string) error {
+	log := config.WithContext(ctx)
+
+	res, err := s.getOwnedResource(ctx, id, "delete")
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteResource(res.ID); err != nil {
+		log.WithError(err).Error("Failed to delete resource")
+		return err
 	}
 
+	log.WithField("resource_id", res.ID).Info("Resource deleted successfully")
 	return nil
 }
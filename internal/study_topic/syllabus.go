@@ -0,0 +1,295 @@
+package studytopic
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+)
+
+var ErrInvalidSyllabus = errors.New("invalid syllabus")
+
+type SyllabusFormat string
+
+const (
+	SYLLABUS_MARKDOWN SyllabusFormat = "markdown"
+	SYLLABUS_CSV      SyllabusFormat = "csv"
+	SYLLABUS_TEXT     SyllabusFormat = "text"
+)
+
+// NestedMode define o destino dos itens aninhados abaixo de um tópico.
+type NestedMode string
+
+const (
+	NESTED_DESCRIPTION NestedMode = "description"
+	NESTED_TOPICS      NestedMode = "topics"
+)
+
+const (
+	maxSyllabusBytes  = 256 * 1024
+	maxSyllabusTopics = 500
+)
+
+type ImportSyllabusDTO struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Format      SyllabusFormat `json:"format"`
+	Nested      NestedMode     `json:"nested"`
+	Content     string         `json:"content"`
+}
+
+type SyllabusImport struct {
+	Subject *studysubject.StudySubject `json:"subject"`
+	Topics  []*StudyTopic              `json:"topics"`
+	Preview bool                       `json:"preview"`
+}
+
+func (d *ImportSyllabusDTO) Validate() error {
+	if strings.TrimSpace(d.Content) == "" {
+		return fmt.Errorf("%w: content is required", ErrInvalidSyllabus)
+	}
+	if len(d.Content) > maxSyllabusBytes {
+		return fmt.Errorf("%w: content exceeds %d bytes", ErrInvalidSyllabus, maxSyllabusBytes)
+	}
+	if d.Format == "" {
+		d.Format = detectSyllabusFormat(d.Content)
+	}
+	switch d.Format {
+	case SYLLABUS_MARKDOWN, SYLLABUS_CSV, SYLLABUS_TEXT:
+	default:
+		return fmt.Errorf("%w: format must be markdown, csv or text", ErrInvalidSyllabus)
+	}
+	if d.Nested == "" {
+		d.Nested = NESTED_DESCRIPTION
+	}
+	if d.Nested != NESTED_DESCRIPTION && d.Nested != NESTED_TOPICS {
+		return fmt.Errorf("%w: nested must be description or topics", ErrInvalidSyllabus)
+	}
+	return nil
+}
+
+// outlineNode é um item do esboço; rank menor fica mais acima na hierarquia.
+type outlineNode struct {
+	title    string
+	notes    []string
+	rank     int
+	children []*outlineNode
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$`)
+	bulletPattern  = regexp.MustCompile(`^([-*+]|\d+[.)])\s+(.+)$`)
+)
+
+// bulletRank fica abaixo de qualquer título para que listas sempre pertençam ao título anterior.
+const bulletRank = 100
+
+func detectSyllabusFormat(content string) SyllabusFormat {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if headingPattern.MatchString(trimmed) || bulletPattern.MatchString(trimmed) {
+			return SYLLABUS_MARKDOWN
+		}
+		if strings.Contains(trimmed, ",") || strings.Contains(trimmed, ";") {
+			return SYLLABUS_CSV
+		}
+		return SYLLABUS_TEXT
+	}
+	return SYLLABUS_TEXT
+}
+
+// indentWidth conta a indentação do início da linha, com tab valendo quatro espaços.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// parseOutline monta a árvore de Markdown ou texto indentado. No Markdown, linhas soltas
+// são anotações do item anterior; no texto puro, toda linha é um item.
+func parseOutline(content string, format SyllabusFormat) []*outlineNode {
+	var roots, stack []*outlineNode
+	var last *outlineNode
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		node := &outlineNode{}
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil && format == SYLLABUS_MARKDOWN {
+			node.title, node.rank = m[2], len(m[1])
+		} else if m := bulletPattern.FindStringSubmatch(trimmed); m != nil {
+			node.title, node.rank = m[2], bulletRank+indentWidth(line)
+		} else if format == SYLLABUS_TEXT || last == nil {
+			node.title, node.rank = trimmed, bulletRank+indentWidth(line)
+		} else {
+			last.notes = append(last.notes, trimmed)
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].rank >= node.rank {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+		}
+		stack = append(stack, node)
+		last = node
+	}
+	return roots
+}
+
+// parseCSVOutline lê uma linha por tópico. Com cabeçalho, usa as colunas name/topic/title
+// e description; sem ele, a primeira coluna é o nome e a segunda a descrição.
+func parseCSVOutline(content string) ([]*outlineNode, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if detectCSVComma(content) == ';' {
+		reader.Comma = ';'
+	}
+
+	nameCol, descCol := 0, 1
+	var roots []*outlineNode
+	first := true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSyllabus, err)
+		}
+
+		if first {
+			first = false
+			if n, d, ok := csvHeader(record); ok {
+				nameCol, descCol = n, d
+				continue
+			}
+		}
+
+		if nameCol >= len(record) || strings.TrimSpace(record[nameCol]) == "" {
+			continue
+		}
+		node := &outlineNode{title: strings.TrimSpace(record[nameCol])}
+		if descCol >= 0 && descCol < len(record) && strings.TrimSpace(record[descCol]) != "" {
+			node.notes = []string{strings.TrimSpace(record[descCol])}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func detectCSVComma(content string) rune {
+	firstLine := strings.SplitN(content, "\n", 2)[0]
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		return ';'
+	}
+	return ','
+}
+
+func csvHeader(record []string) (nameCol, descCol int, ok bool) {
+	nameCol, descCol = -1, -1
+	for i, col := range record {
+		switch strings.ToLower(strings.TrimSpace(col)) {
+		case "name", "topic", "title":
+			nameCol = i
+		case "description":
+			descCol = i
+		}
+	}
+	return nameCol, descCol, nameCol >= 0
+}
+
+// describe junta as anotações do item e, recursivamente, os filhos como lista Markdown.
+func describe(node *outlineNode, depth int) []string {
+	var lines []string
+	if depth == 0 {
+		lines = append(lines, node.notes...)
+	}
+	for _, child := range node.children {
+		lines = append(lines, strings.Repeat("  ", depth)+"- "+child.title)
+		lines = append(lines, describe(child, depth+1)...)
+	}
+	return lines
+}
+
+// flattenOutline transforma a árvore em tópicos ordenados, em pré-ordem.
+func flattenOutline(roots []*outlineNode, nested NestedMode) []*StudyTopic {
+	var topics []*StudyTopic
+	var walk func(node *outlineNode, parent string)
+	walk = func(node *outlineNode, parent string) {
+		topic := &StudyTopic{Name: node.title}
+		if nested == NESTED_DESCRIPTION {
+			topic.Description = strings.Join(describe(node, 0), "\n")
+			topics = append(topics, topic)
+			return
+		}
+
+		notes := node.notes
+		if parent != "" {
+			notes = append([]string{"Subtopic of " + parent}, notes...)
+		}
+		topic.Description = strings.Join(notes, "\n")
+		topics = append(topics, topic)
+		for _, child := range node.children {
+			walk(child, node.title)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "")
+	}
+	return topics
+}
+
+// parseSyllabus devolve o nome sugerido para a matéria e os tópicos na ordem do esboço.
+// Um único item raiz com filhos é tratado como título do documento.
+func parseSyllabus(dto *ImportSyllabusDTO) (string, string, []*StudyTopic, error) {
+	var roots []*outlineNode
+	if dto.Format == SYLLABUS_CSV {
+		var err error
+		if roots, err = parseCSVOutline(dto.Content); err != nil {
+			return "", "", nil, err
+		}
+	} else {
+		roots = parseOutline(dto.Content, dto.Format)
+	}
+
+	var title, notes string
+	if len(roots) == 1 && len(roots[0].children) > 0 {
+		title = roots[0].title
+		notes = strings.Join(roots[0].notes, "\n")
+		roots = roots[0].children
+	}
+
+	topics := flattenOutline(roots, dto.Nested)
+	if len(topics) == 0 {
+		return "", "", nil, fmt.Errorf("%w: no topics found", ErrInvalidSyllabus)
+	}
+	if len(topics) > maxSyllabusTopics {
+		return "", "", nil, fmt.Errorf("%w: at most %d topics per import", ErrInvalidSyllabus, maxSyllabusTopics)
+	}
+	return title, notes, topics, nil
+}
@@ -0,0 +1,271 @@
+package task
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/flashcard"
+	"github.com/saulo-duarte/chronos-lambda/internal/milestone"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const (
+	cloneNameSuffix    = " (cópia)"
+	maxCloneOffsetDays = 3650
+)
+
+var (
+	ErrStudySubjectNotFound = studysubject.ErrStudySubjectNotFound
+	ErrInvalidCloneOptions  = errors.New("invalid clone options")
+)
+
+// CloneOptions controla a cópia: nome da nova raiz e deslocamento das datas em dias.
+type CloneOptions struct {
+	Name       string `json:"name"`
+	OffsetDays int    `json:"offsetDays"`
+}
+
+func (o *CloneOptions) Validate() error {
+	if o.OffsetDays > maxCloneOffsetDays || o.OffsetDays < -maxCloneOffsetDays {
+		return ErrInvalidCloneOptions
+	}
+	return nil
+}
+
+type ProjectTree struct {
+	Project      *project.Project       `json:"project"`
+	Milestones   []*milestone.Milestone `json:"milestones"`
+	Tasks        []*Task                `json:"tasks"`
+	Dependencies []TaskDependency       `json:"dependencies"`
+}
+
+type SubjectTree struct {
+	Subject       *studysubject.StudySubject     `json:"subject"`
+	Topics        []*studytopic.StudyTopic       `json:"topics"`
+	Tasks         []*Task                        `json:"tasks"`
+	Flashcards    []*flashcard.Flashcard         `json:"flashcards"`
+	Prerequisites []studytopic.TopicPrerequisite `json:"prerequisites"`
+	Resources     []*studytopic.Resource         `json:"resources"`
+}
+
+func shiftDate(d *util.LocalDateTime, days int) *util.LocalDateTime {
+	if d == nil {
+		return nil
+	}
+	return &util.LocalDateTime{Time: d.AddDate(0, 0, days)}
+}
+
+// cloneTask copia os dados de planejamento da task, zerando status, progresso e evento do Google.
+func cloneTask(src *Task, userID uuid.UUID, offsetDays int, now time.Time) *Task {
+	return &Task{
+		ID:               uuid.New(),
+		Name:             src.Name,
+		Description:      src.Description,
+		Status:           TODO,
+		Type:             src.Type,
+		Priority:         src.Priority,
+		StartDate:        shiftDate(src.StartDate, offsetDays),
+		DueDate:          shiftDate(src.DueDate, offsetDays),
+		EstimatedMinutes: src.EstimatedMinutes,
+		UserID:           userID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// cloneProjectTree gera a nova árvore com IDs novos, remapeando marcos e dependências.
+func cloneProjectTree(src *ProjectTree, userID uuid.UUID, opts CloneOptions, now time.Time) *ProjectTree {
+	name := opts.Name
+	if name == "" {
+		name = src.Project.Title + cloneNameSuffix
+	}
+
+	p := &project.Project{
+		ID:          uuid.New(),
+		Title:       name,
+		Description: src.Project.Description,
+		Status:      project.NOT_INITIALIZED,
+		UserID:      userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if src.Project.Deadline != nil {
+		deadline := src.Project.Deadline.AddDate(0, 0, opts.OffsetDays)
+		p.Deadline = &deadline
+	}
+
+	tree := &ProjectTree{
+		Project:      p,
+		Milestones:   make([]*milestone.Milestone, 0, len(src.Milestones)),
+		Tasks:        make([]*Task, 0, len(src.Tasks)),
+		Dependencies: make([]TaskDependency, 0, len(src.Dependencies)),
+	}
+
+	milestoneIDs := make(map[uuid.UUID]uuid.UUID, len(src.Milestones))
+	for _, m := range src.Milestones {
+		clone := &milestone.Milestone{
+			ID:          uuid.New(),
+			Title:       m.Title,
+			Description: m.Description,
+			TargetDate:  shiftDate(m.TargetDate, opts.OffsetDays),
+			Status:      milestone.PENDING,
+			ProjectID:   p.ID,
+			UserID:      userID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		milestoneIDs[m.ID] = clone.ID
+		tree.Milestones = append(tree.Milestones, clone)
+	}
+
+	taskIDs := make(map[uuid.UUID]uuid.UUID, len(src.Tasks))
+	for _, t := range src.Tasks {
+		clone := cloneTask(t, userID, opts.OffsetDays, now)
+		clone.ProjectId = &p.ID
+		if t.MilestoneId != nil {
+			if id, ok := milestoneIDs[*t.MilestoneId]; ok {
+				clone.MilestoneId = &id
+			}
+		}
+		// O tópico só acompanha a cópia quando pertence a quem está clonando.
+		if t.StudyTopicId != nil && auth.IsOwner(userID.String(), t.UserID) {
+			topicID := *t.StudyTopicId
+			clone.StudyTopicId = &topicID
+		}
+		taskIDs[t.ID] = clone.ID
+		tree.Tasks = append(tree.Tasks, clone)
+	}
+
+	for _, d := range src.Dependencies {
+		taskID, okTask := taskIDs[d.TaskID]
+		dependsOnID, okDep := taskIDs[d.DependsOnID]
+		if okTask && okDep {
+			tree.Dependencies = append(tree.Dependencies, TaskDependency{TaskID: taskID, DependsOnID: dependsOnID})
+		}
+	}
+
+	return tree
+}
+
+func cloneSubjectTree(src *SubjectTree, userID uuid.UUID, opts CloneOptions, now time.Time) *SubjectTree {
+	name := opts.Name
+	if name == "" {
+		name = src.Subject.Name + cloneNameSuffix
+	}
+
+	subject := &studysubject.StudySubject{
+		ID:           uuid.New(),
+		Name:         name,
+		Description:  src.Subject.Description,
+		WeeklyTarget: src.Subject.WeeklyTarget,
+		UserID:       userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	tree := &SubjectTree{
+		Subject:       subject,
+		Topics:        make([]*studytopic.StudyTopic, 0, len(src.Topics)),
+		Tasks:         make([]*Task, 0, len(src.Tasks)),
+		Flashcards:    make([]*flashcard.Flashcard, 0, len(src.Flashcards)),
+		Prerequisites: make([]studytopic.TopicPrerequisite, 0, len(src.Prerequisites)),
+		Resources:     make([]*studytopic.Resource, 0, len(src.Resources)),
+	}
+
+	topicIDs := make(map[uuid.UUID]uuid.UUID, len(src.Topics))
+	// posições são renumeradas na ordem de origem para respeitar o índice único
+	for i, t := range src.Topics {
+		clone := &studytopic.StudyTopic{
+			ID:             uuid.New(),
+			Name:           t.Name,
+			Description:    t.Description,
+			Position:       i + 1,
+			Mastery:        studytopic.NOT_STARTED,
+			UserID:         userID,
+			StudySubjectID: subject.ID,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		topicIDs[t.ID] = clone.ID
+		tree.Topics = append(tree.Topics, clone)
+	}
+
+	for _, t := range src.Tasks {
+		if t.StudyTopicId == nil {
+			continue
+		}
+		topicID, ok := topicIDs[*t.StudyTopicId]
+		if !ok {
+			continue
+		}
+		clone := cloneTask(t, userID, opts.OffsetDays, now)
+		clone.StudyTopicId = &topicID
+		tree.Tasks = append(tree.Tasks, clone)
+	}
+
+	// cartões são copiados como novos, sem o histórico de revisões; o CreatedAt crescente
+	// mantém a ordem original, como na criação em lote
+	for i, c := range src.Flashcards {
+		topicID, ok := topicIDs[c.TopicID]
+		if !ok {
+			continue
+		}
+		tree.Flashcards = append(tree.Flashcards, &flashcard.Flashcard{
+			ID:        uuid.New(),
+			Front:     c.Front,
+			Back:      c.Back,
+			Tags:      c.Tags,
+			TopicID:   topicID,
+			UserID:    userID,
+			SRSState:  util.NewSRSState(),
+			CreatedAt: now.Add(time.Duration(i) * time.Microsecond),
+			UpdatedAt: now,
+		})
+	}
+
+	// materiais são copiados sem o progresso de leitura
+	for _, res := range src.Resources {
+		topicID, ok := topicIDs[res.TopicID]
+		if !ok {
+			continue
+		}
+		tree.Resources = append(tree.Resources, &studytopic.Resource{
+			ID:         uuid.New(),
+			TopicID:    topicID,
+			UserID:     userID,
+			Kind:       res.Kind,
+			Title:      res.Title,
+			URL:        res.URL,
+			Reference:  res.Reference,
+			Unit:       res.Unit,
+			TotalUnits: res.TotalUnits,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+
+	// pré-requisitos de outras matérias continuam apontando para o tópico original
+	for _, p := range src.Prerequisites {
+		topicID, ok := topicIDs[p.TopicID]
+		if !ok {
+			continue
+		}
+		prerequisiteID, ok := topicIDs[p.PrerequisiteID]
+		if !ok {
+			prerequisiteID = p.PrerequisiteID
+		}
+		tree.Prerequisites = append(tree.Prerequisites, studytopic.TopicPrerequisite{
+			TopicID:        topicID,
+			PrerequisiteID: prerequisiteID,
+			UserID:         userID,
+			CreatedAt:      now,
+		})
+	}
+
+	return tree
+}
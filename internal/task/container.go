@@ -1,7 +1,10 @@
 package task
 
 import (
+	"github.com/saulo-duarte/chronos-lambda/internal/flashcard"
+	"github.com/saulo-duarte/chronos-lambda/internal/milestone"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
 	"gorm.io/gorm"
@@ -16,9 +19,13 @@ func NewTaskContainer(
 	projectService project.ProjectService,
 	studyTopicRepo studytopic.StudyTopicRepository,
 	userRepository user.UserRepository,
+	milestoneRepo milestone.MilestoneRepository,
+	flashcardRepo flashcard.FlashcardRepository,
+	busyProvider BusySlotProvider,
 ) *TaskContainer {
 	repo := NewRepository(db)
-	service := NewService(repo, projectService, userRepository, studyTopicRepo)
+	studySubjectRepo := studysubject.NewRepository(db)
+	service := NewService(repo, projectService, userRepository, studyTopicRepo, studySubjectRepo, milestoneRepo, flashcardRepo, busyProvider)
 	handler := NewHandler(service)
 
 	return &TaskContainer{
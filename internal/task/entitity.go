@@ -20,13 +20,21 @@ type Task struct {
 	Priority              TaskPriority          `json:"priority"`
 	StartDate             *util.LocalDateTime   `json:"startDate"`
 	DueDate               *util.LocalDateTime   `json:"dueDate"`
+	EstimatedMinutes      int                   `json:"estimatedMinutes"`
+	LoggedMinutes         int                   `json:"loggedMinutes"`
 	ProjectId             *uuid.UUID            `json:"projectId"`
 	Project               project.Project       `gorm:"foreignKey:ProjectId" json:"project"`
+	MilestoneId           *uuid.UUID            `json:"milestoneId"`
 	StudyTopicId          *uuid.UUID            `json:"studyTopicId"`
 	StudyTopic            studytopic.StudyTopic `gorm:"foreignKey:StudyTopicId" json:"studyTopic"`
+	AssigneeId            *uuid.UUID            `json:"assigneeId"`
+	StudyPlanId           *uuid.UUID            `json:"studyPlanId"`
+	AssessmentId          *uuid.UUID            `json:"assessmentId"`
 	UserID                uuid.UUID             `gorm:"column:user_id;not null" json:"userId"`
 	User                  user.User             `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
 	DoneAt                time.Time             `json:"doneAt"`
 	CreatedAt             time.Time             `json:"createdAt"`
 	UpdatedAt             time.Time             `json:"updatedAt"`
+	Warning               string                `gorm:"-" json:"warning,omitempty"`
+	UnmetPrerequisites    []uuid.UUID           `gorm:"-" json:"unmetPrerequisites,omitempty"`
 }
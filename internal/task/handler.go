@@ -1,8 +1,10 @@
 package task
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -30,6 +32,18 @@ func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 
 	task, err := h.service.CreateTask(r.Context(), &payload)
 	if err != nil {
+		if errors.Is(err, ErrMilestoneNotFound) {
+			http.Error(w, "milestone not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrInvalidAssignee) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		log.WithError(err).Error("Falha ao criar task")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -49,6 +63,10 @@ func (h *Handler) GetTask(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "task not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		log.WithError(err).Error("Erro ao buscar task")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -108,6 +126,18 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "task not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, ErrMilestoneNotFound) {
+			http.Error(w, "milestone not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrInvalidAssignee) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		log.WithError(err).Error("Erro ao atualizar task")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -126,6 +156,10 @@ func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "task not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
 		log.WithError(err).Error("Erro ao excluir task")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -154,3 +188,244 @@ func (h *Handler) ListTasksByStudyTopic(w http.ResponseWriter, r *http.Request)
 
 	config.JSON(w, http.StatusOK, tasks)
 }
+
+func (h *Handler) PreviewSchedule(w http.ResponseWriter, r *http.Request) {
+	h.handleSchedule(w, r, h.service.PreviewSchedule)
+}
+
+func (h *Handler) ApplySchedule(w http.ResponseWriter, r *http.Request) {
+	h.handleSchedule(w, r, h.service.ApplySchedule)
+}
+
+func (h *Handler) handleSchedule(w http.ResponseWriter, r *http.Request, run func(context.Context, *SchedulePreferences) (*SchedulePlan, error)) {
+	log := config.WithContext(r.Context())
+
+	var prefs SchedulePreferences
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+			log.WithError(err).Error("Corpo da requisição inválido")
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	plan, err := run(r.Context(), &prefs)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorized):
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case errors.Is(err, ErrInvalidSchedulePreferences):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrScheduleConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			log.WithError(err).Error("Erro ao gerar agenda de tasks")
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	config.JSON(w, http.StatusOK, plan)
+}
+
+type addDependencyPayload struct {
+	DependsOnID string `json:"dependsOnId"`
+}
+
+func (h *Handler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	var payload addDependencyPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		log.WithError(err).Error("Corpo da requisição inválido")
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dep, err := h.service.AddDependency(r.Context(), chi.URLParam(r, "taskID"), payload.DependsOnID)
+	if err != nil {
+		writeDependencyError(w, r, err, "Erro ao adicionar dependência")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, dep)
+}
+
+func (h *Handler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveDependency(r.Context(), chi.URLParam(r, "taskID"), chi.URLParam(r, "dependsOnID")); err != nil {
+		writeDependencyError(w, r, err, "Erro ao remover dependência")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "dependency removed successfully",
+	})
+}
+
+func (h *Handler) GetProjectTimeline(w http.ResponseWriter, r *http.Request) {
+	timeline, err := h.service.GetProjectTimeline(r.Context(), chi.URLParam(r, "projectId"))
+	if err != nil {
+		writeDependencyError(w, r, err, "Erro ao gerar timeline do projeto")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, timeline)
+}
+
+func writeDependencyError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, ErrTaskNotFound):
+		http.Error(w, "task not found", http.StatusNotFound)
+	case errors.Is(err, ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidID), errors.Is(err, ErrInvalidDependency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrDependencyCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+// decodeCloneOptions aceita corpo vazio, usando os valores padrão da cópia.
+func decodeCloneOptions(r *http.Request) (CloneOptions, error) {
+	var opts CloneOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && !errors.Is(err, io.EOF) {
+		return opts, err
+	}
+	return opts, nil
+}
+
+func (h *Handler) CloneProject(w http.ResponseWriter, r *http.Request) {
+	opts, err := decodeCloneOptions(r)
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tree, err := h.service.CloneProject(r.Context(), chi.URLParam(r, "projectId"), opts)
+	if err != nil {
+		writeCloneError(w, r, err, "Erro ao clonar projeto")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, tree)
+}
+
+func (h *Handler) CloneStudySubject(w http.ResponseWriter, r *http.Request) {
+	opts, err := decodeCloneOptions(r)
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tree, err := h.service.CloneStudySubject(r.Context(), chi.URLParam(r, "studySubjectId"), opts)
+	if err != nil {
+		writeCloneError(w, r, err, "Erro ao clonar matéria")
+		return
+	}
+
+	config.JSON(w, http.StatusCreated, tree)
+}
+
+func writeCloneError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidID), errors.Is(err, ErrInvalidCloneOptions):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) ListDueReviews(w http.ResponseWriter, r *http.Request) {
+	createTasks := r.URL.Query().Get("createTasks") == "true"
+
+	due, err := h.service.ListDueReviews(r.Context(), createTasks)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		config.WithContext(r.Context()).WithError(err).Error("Erro ao listar revisões pendentes")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]interface{}{
+		"count":        len(due.Reviews),
+		"reviews":      due.Reviews,
+		"createdTasks": due.CreatedTasks,
+	})
+}
+
+func (h *Handler) CreateStudyPlan(w http.ResponseWriter, r *http.Request) {
+	var req StudyPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preview := r.URL.Query().Get("preview") == "true"
+	plan, err := h.service.CreateStudyPlan(r.Context(), chi.URLParam(r, "studySubjectId"), &req, preview)
+	if err != nil {
+		writeStudyPlanError(w, r, err, "Erro ao gerar plano de estudos")
+		return
+	}
+
+	status := http.StatusCreated
+	if preview {
+		status = http.StatusOK
+	}
+	config.JSON(w, status, plan)
+}
+
+func (h *Handler) GetStudyPlan(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.service.GetStudyPlan(r.Context(), chi.URLParam(r, "planId"))
+	if err != nil {
+		writeStudyPlanError(w, r, err, "Erro ao buscar plano de estudos")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, plan)
+}
+
+func (h *Handler) ReplanStudyPlan(w http.ResponseWriter, r *http.Request) {
+	preview := r.URL.Query().Get("preview") == "true"
+	plan, err := h.service.ReplanStudyPlan(r.Context(), chi.URLParam(r, "planId"), preview)
+	if err != nil {
+		writeStudyPlanError(w, r, err, "Erro ao replanejar plano de estudos")
+		return
+	}
+
+	config.JSON(w, http.StatusOK, plan)
+}
+
+func writeStudyPlanError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrStudyPlanNotFound):
+		http.Error(w, "study plan not found", http.StatusNotFound)
+	case errors.Is(err, ErrStudySubjectNotFound):
+		http.Error(w, "study subject not found", http.StatusNotFound)
+	case errors.Is(err, ErrInvalidID), errors.Is(err, ErrInvalidStudyPlan):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		config.WithContext(r.Context()).WithError(err).Error(msg)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
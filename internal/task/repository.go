@@ -2,23 +2,38 @@ package task
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
-	ErrNotFound = errors.New("task not found")
+	ErrNotFound         = errors.New("task not found")
+	ErrScheduleConflict = errors.New("task was scheduled or removed concurrently")
 )
 
 type TaskRepository interface {
 	Create(t *Task) error
-	FindByIdAndUserId(id, userId uuid.UUID) (*Task, error)
+	FindByID(id uuid.UUID) (*Task, error)
 	ListByUser(userId uuid.UUID) ([]*Task, error)
-	ListByProjectAndUser(projectId, userId uuid.UUID) ([]*Task, error)
+	ListByProject(projectId uuid.UUID) ([]*Task, error)
 	ListByStudyTopicAndUser(topicId, userId uuid.UUID) ([]*Task, error)
+	ListByStudyTopics(topicIds []uuid.UUID, userId uuid.UUID) ([]*Task, error)
+	ListOpenByUser(userId uuid.UUID) ([]*Task, error)
+	ApplySchedule(userId uuid.UUID, slots []ScheduledTask) error
 	Update(t *Task) error
-	Delete(id, userId uuid.UUID) error
+	Delete(id uuid.UUID) error
+	AddDependency(d *TaskDependency) error
+	RemoveDependency(taskID, dependsOnID uuid.UUID) error
+	ListDependenciesByProject(projectID uuid.UUID) ([]TaskDependency, error)
+	CreateProjectTree(tree *ProjectTree) error
+	CreateSubjectTree(tree *SubjectTree) error
+	GetStudyPlan(id uuid.UUID) (*StudyPlan, error)
+	ListByStudyPlan(planID uuid.UUID) ([]*Task, error)
+	CreateStudyPlan(plan *StudyPlan, tasks []*Task) error
+	ReplanStudyPlan(plan *StudyPlan, tasks []*Task) error
 }
 
 type taskRepository struct {
@@ -33,9 +48,9 @@ func (r *taskRepository) Create(t *Task) error {
 	return r.db.Create(t).Error
 }
 
-func (r *taskRepository) FindByIdAndUserId(id, userId uuid.UUID) (*Task, error) {
+func (r *taskRepository) FindByID(id uuid.UUID) (*Task, error) {
 	var t Task
-	if err := r.db.Where("id = ? AND user_id = ?", id, userId).First(&t).Error; err != nil {
+	if err := r.db.Where("id = ?", id).First(&t).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound
 		}
@@ -46,15 +61,15 @@ func (r *taskRepository) FindByIdAndUserId(id, userId uuid.UUID) (*Task, error)
 
 func (r *taskRepository) ListByUser(userId uuid.UUID) ([]*Task, error) {
 	var tasks []*Task
-	if err := r.db.Preload("Project").Preload("StudyTopic").Where("user_id = ?", userId).Find(&tasks).Error; err != nil {
+	if err := r.db.Preload("Project").Preload("StudyTopic").Where("user_id = ? OR assignee_id = ?", userId, userId).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
-func (r *taskRepository) ListByProjectAndUser(projectId, userId uuid.UUID) ([]*Task, error) {
+func (r *taskRepository) ListByProject(projectId uuid.UUID) ([]*Task, error) {
 	var tasks []*Task
-	if err := r.db.Preload("Project").Preload("StudyTopic").Where("project_id = ? AND user_id = ?", projectId, userId).Find(&tasks).Error; err != nil {
+	if err := r.db.Preload("Project").Preload("StudyTopic").Where("project_id = ?", projectId).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
@@ -68,14 +83,228 @@ func (r *taskRepository) ListByStudyTopicAndUser(topicId, userId uuid.UUID) ([]*
 	return tasks, nil
 }
 
+func (r *taskRepository) ListByStudyTopics(topicIds []uuid.UUID, userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if len(topicIds) == 0 {
+		return tasks, nil
+	}
+	if err := r.db.Where("study_topic_id IN ? AND user_id = ?", topicIds, userId).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListOpenByUser(userId uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if err := r.db.Where("user_id = ? AND status <> ?", userId, DONE).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ApplySchedule(userId uuid.UUID, slots []ScheduledTask) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, slot := range slots {
+			result := tx.Model(&Task{}).
+				Where("id = ? AND user_id = ? AND start_date IS NULL", slot.TaskID, userId).
+				Updates(map[string]interface{}{
+					"start_date": slot.StartDate,
+					"updated_at": time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrScheduleConflict
+			}
+		}
+		return nil
+	})
+}
+
 func (r *taskRepository) Update(t *Task) error {
 	return r.db.Save(t).Error
 }
 
-func (r *taskRepository) Delete(id, userId uuid.UUID) error {
-	result := r.db.Where("id = ? AND user_id = ?", id, userId).Delete(&Task{})
+func (r *taskRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ? OR depends_on_id = ?", id, id).Delete(&TaskDependency{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&Task{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *taskRepository) AddDependency(d *TaskDependency) error {
+	return r.db.Create(d).Error
+}
+
+func (r *taskRepository) RemoveDependency(taskID, dependsOnID uuid.UUID) error {
+	result := r.db.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).Delete(&TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
 		return ErrNotFound
 	}
-	return result.Error
+	return nil
+}
+
+func (r *taskRepository) ListDependenciesByProject(projectID uuid.UUID) ([]TaskDependency, error) {
+	var deps []TaskDependency
+	err := r.db.Table("task_dependencies AS d").
+		Select("d.*").
+		Joins("JOIN tasks t ON t.id = d.task_id").
+		Where("t.project_id = ?", projectID).
+		Scan(&deps).Error
+	if err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// CreateProjectTree grava projeto, marcos, tasks e dependências clonados numa única transação.
+func (r *taskRepository) CreateProjectTree(tree *ProjectTree) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(tree.Project).Error; err != nil {
+			return err
+		}
+		if len(tree.Milestones) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&tree.Milestones).Error; err != nil {
+				return err
+			}
+		}
+		if len(tree.Tasks) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&tree.Tasks).Error; err != nil {
+				return err
+			}
+		}
+		if len(tree.Dependencies) > 0 {
+			if err := tx.Create(&tree.Dependencies).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateSubjectTree grava matéria, tópicos, tasks, cartões, pré-requisitos e materiais clonados numa única transação.
+func (r *taskRepository) CreateSubjectTree(tree *SubjectTree) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(tree.Subject).Error; err != nil {
+			return err
+		}
+		if len(tree.Topics) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&tree.Topics).Error; err != nil {
+				return err
+			}
+		}
+		if len(tree.Tasks) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&tree.Tasks).Error; err != nil {
+				return err
+			}
+		}
+		if len(tree.Flashcards) > 0 {
+			if err := tx.Create(&tree.Flashcards).Error; err != nil {
+				return err
+			}
+		}
+		if len(tree.Prerequisites) > 0 {
+			if err := tx.Create(&tree.Prerequisites).Error; err != nil {
+				return err
+			}
+		}
+		if len(tree.Resources) > 0 {
+			if err := tx.Create(&tree.Resources).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *taskRepository) GetStudyPlan(id uuid.UUID) (*StudyPlan, error) {
+	var plan StudyPlan
+	if err := r.db.First(&plan, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *taskRepository) ListByStudyPlan(planID uuid.UUID) ([]*Task, error) {
+	var tasks []*Task
+	if err := r.db.Where("study_plan_id = ?", planID).Order("start_date").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// deleteOpenPlanTasks remove as sessões ainda não concluídas dos planos informados.
+func deleteOpenPlanTasks(tx *gorm.DB, planIDs []uuid.UUID) error {
+	open := tx.Model(&Task{}).Select("id").Where("study_plan_id IN ? AND status <> ?", planIDs, DONE)
+	if err := tx.Where("task_id IN (?) OR depends_on_id IN (?)", open, open).Delete(&TaskDependency{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("study_plan_id IN ? AND status <> ?", planIDs, DONE).Delete(&Task{}).Error
+}
+
+// CreateStudyPlan substitui os planos anteriores da matéria: as sessões abertas deles são
+// removidas e as concluídas passam a fazer parte do novo plano.
+func (r *taskRepository) CreateStudyPlan(plan *StudyPlan, tasks []*Task) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous []uuid.UUID
+		if err := tx.Model(&StudyPlan{}).
+			Where("subject_id = ? AND user_id = ?", plan.SubjectID, plan.UserID).
+			Pluck("id", &previous).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(plan).Error; err != nil {
+			return err
+		}
+
+		if len(previous) > 0 {
+			if err := deleteOpenPlanTasks(tx, previous); err != nil {
+				return err
+			}
+			if err := tx.Model(&Task{}).Where("study_plan_id IN ?", previous).
+				Update("study_plan_id", plan.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&StudyPlan{}, "id IN ?", previous).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(tasks) > 0 {
+			return tx.Omit(clause.Associations).Create(&tasks).Error
+		}
+		return nil
+	})
+}
+
+// ReplanStudyPlan troca as sessões abertas do plano pelas novas numa única transação.
+func (r *taskRepository) ReplanStudyPlan(plan *StudyPlan, tasks []*Task) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteOpenPlanTasks(tx, []uuid.UUID{plan.ID}); err != nil {
+			return err
+		}
+		if err := tx.Model(plan).Select("review_start", "updated_at").Updates(plan).Error; err != nil {
+			return err
+		}
+		if len(tasks) > 0 {
+			return tx.Omit(clause.Associations).Create(&tasks).Error
+		}
+		return nil
+	})
 }
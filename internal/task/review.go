@@ -0,0 +1,47 @@
+package task
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const reviewTaskPrefix = "Revisão: "
+
+type DueReviews struct {
+	Reviews      []*studytopic.DueReview `json:"reviews"`
+	CreatedTasks []*Task                 `json:"createdTasks"`
+}
+
+// reviewTask monta a task STUDY de uma revisão vencida, com prazo no fim do dia local (today
+// vem de util.LocalNow).
+func reviewTask(due *studytopic.DueReview, userID uuid.UUID, today, now time.Time) *Task {
+	topicID := due.Topic.ID
+	endOfDay := time.Date(today.Year(), today.Month(), today.Day(), 23, 59, 0, 0, time.UTC)
+
+	return &Task{
+		ID:           uuid.New(),
+		Name:         reviewTaskPrefix + due.Topic.Name,
+		Status:       TODO,
+		Type:         STUDY,
+		Priority:     MEDIUM,
+		DueDate:      &util.LocalDateTime{Time: endOfDay},
+		StudyTopicId: &topicID,
+		UserID:       userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// openStudyTopics indica os tópicos que já têm task STUDY em aberto, para não duplicar revisões.
+func openStudyTopics(tasks []*Task) map[uuid.UUID]bool {
+	open := make(map[uuid.UUID]bool)
+	for _, t := range tasks {
+		if t.Type == STUDY && t.Status != DONE && t.StudyTopicId != nil {
+			open[*t.StudyTopicId] = true
+		}
+	}
+	return open
+}
@@ -8,11 +8,15 @@ func Routes(h *Handler) chi.Router {
 	r := chi.NewRouter()
 
 	r.Post("/", h.CreateTask)
+	r.Post("/schedule/preview", h.PreviewSchedule)
+	r.Post("/schedule/apply", h.ApplySchedule)
 	r.Get("/{taskID}", h.GetTask)
 	r.Get("/", h.ListTasksByUser)
 	r.Get("/project/{projectID}", h.ListTasksByProject)
 	r.Put("/{taskID}", h.UpdateTask)
 	r.Delete("/{taskID}", h.DeleteTask)
+	r.Post("/{taskID}/dependencies", h.AddDependency)
+	r.Delete("/{taskID}/dependencies/{dependsOnID}", h.RemoveDependency)
 
 	return r
 }
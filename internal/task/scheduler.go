@@ -0,0 +1,283 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const (
+	defaultWorkdayStart     = "09:00"
+	defaultWorkdayEnd       = "18:00"
+	defaultHorizonDays      = 14
+	maxHorizonDays          = 90
+	defaultEstimatedMinutes = 60
+	scheduleSlotGranularity = 15 * time.Minute
+)
+
+var ErrInvalidSchedulePreferences = errors.New("invalid schedule preferences")
+
+// TimeSlot representa um intervalo [Start, End) ocupado ou livre na agenda.
+type TimeSlot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BusySlotProvider fornece blocos ocupados externos (ex.: Google Calendar).
+type BusySlotProvider interface {
+	BusySlots(ctx context.Context, accessToken string, from, to time.Time) ([]TimeSlot, error)
+}
+
+type SchedulePreferences struct {
+	WorkdayStart           string              `json:"workdayStart"`
+	WorkdayEnd             string              `json:"workdayEnd"`
+	WorkDays               []time.Weekday      `json:"workDays"`
+	From                   *util.LocalDateTime `json:"from"`
+	HorizonDays            int                 `json:"horizonDays"`
+	DefaultEstimateMinutes int                 `json:"defaultEstimateMinutes"`
+	IncludeGoogleCalendar  bool                `json:"includeGoogleCalendar"`
+	TaskIDs                []uuid.UUID         `json:"taskIds"`
+}
+
+type ScheduledTask struct {
+	TaskID    uuid.UUID           `json:"taskId"`
+	Name      string              `json:"name"`
+	Priority  TaskPriority        `json:"priority"`
+	StartDate *util.LocalDateTime `json:"startDate"`
+	EndDate   *util.LocalDateTime `json:"endDate"`
+	DueDate   *util.LocalDateTime `json:"dueDate"`
+}
+
+type UnscheduledTask struct {
+	TaskID uuid.UUID `json:"taskId"`
+	Name   string    `json:"name"`
+	Reason string    `json:"reason"`
+}
+
+type SchedulePlan struct {
+	From        *util.LocalDateTime `json:"from"`
+	Until       *util.LocalDateTime `json:"until"`
+	Scheduled   []ScheduledTask     `json:"scheduled"`
+	Unscheduled []UnscheduledTask   `json:"unscheduled"`
+}
+
+// workingHours é a janela diária normalizada a partir das preferências.
+type workingHours struct {
+	start    time.Duration
+	end      time.Duration
+	workDays map[time.Weekday]bool
+}
+
+func (p *SchedulePreferences) normalize(now time.Time) (*workingHours, time.Time, time.Time, error) {
+	if p.WorkdayStart == "" {
+		p.WorkdayStart = defaultWorkdayStart
+	}
+	if p.WorkdayEnd == "" {
+		p.WorkdayEnd = defaultWorkdayEnd
+	}
+	if len(p.WorkDays) == 0 {
+		p.WorkDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	if p.HorizonDays <= 0 {
+		p.HorizonDays = defaultHorizonDays
+	}
+	if p.HorizonDays > maxHorizonDays {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: horizonDays cannot exceed %d", ErrInvalidSchedulePreferences, maxHorizonDays)
+	}
+	if p.DefaultEstimateMinutes <= 0 {
+		p.DefaultEstimateMinutes = defaultEstimatedMinutes
+	}
+
+	start, err := parseClock(p.WorkdayStart)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	end, err := parseClock(p.WorkdayEnd)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	if end <= start {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: workdayEnd must be after workdayStart", ErrInvalidSchedulePreferences)
+	}
+
+	days := make(map[time.Weekday]bool, len(p.WorkDays))
+	for _, d := range p.WorkDays {
+		if d < time.Sunday || d > time.Saturday {
+			return nil, time.Time{}, time.Time{}, fmt.Errorf("%w: invalid work day %d", ErrInvalidSchedulePreferences, d)
+		}
+		days[d] = true
+	}
+
+	from := now
+	if p.From != nil && !p.From.IsZero() {
+		from = p.From.Time
+	}
+	from = roundUp(from, scheduleSlotGranularity)
+	until := startOfDay(from).AddDate(0, 0, p.HorizonDays)
+
+	return &workingHours{start: start, end: end, workDays: days}, from, until, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: time %q must use HH:MM format", ErrInvalidSchedulePreferences, s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func roundUp(t time.Time, d time.Duration) time.Time {
+	r := t.Truncate(d)
+	if r.Before(t) {
+		r = r.Add(d)
+	}
+	return r
+}
+
+func priorityWeight(p TaskPriority) int {
+	switch p {
+	case HIGH:
+		return 0
+	case MEDIUM:
+		return 1
+	case LOW:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func taskEstimate(t *Task, fallbackMinutes int) time.Duration {
+	if t.EstimatedMinutes > 0 {
+		return time.Duration(t.EstimatedMinutes) * time.Minute
+	}
+	return time.Duration(fallbackMinutes) * time.Minute
+}
+
+// busyFromTasks converte tasks já agendadas em blocos ocupados.
+func busyFromTasks(tasks []*Task, fallbackMinutes int) []TimeSlot {
+	var busy []TimeSlot
+	for _, t := range tasks {
+		if t.Status == DONE || t.StartDate == nil || t.StartDate.IsZero() {
+			continue
+		}
+		start := t.StartDate.Time
+		end := start.Add(taskEstimate(t, fallbackMinutes))
+		if t.DueDate != nil && t.DueDate.After(start) && t.EstimatedMinutes == 0 {
+			end = t.DueDate.Time
+		}
+		busy = append(busy, TimeSlot{Start: start, End: end})
+	}
+	return busy
+}
+
+// freeSlots devolve os intervalos livres dentro do horário de trabalho entre from e until.
+func freeSlots(hours *workingHours, from, until time.Time, busy []TimeSlot) []TimeSlot {
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	var free []TimeSlot
+	for day := startOfDay(from); day.Before(until); day = day.AddDate(0, 0, 1) {
+		if !hours.workDays[day.Weekday()] {
+			continue
+		}
+		windowStart := day.Add(hours.start)
+		windowEnd := day.Add(hours.end)
+		if windowStart.Before(from) {
+			windowStart = from
+		}
+		if !windowStart.Before(windowEnd) {
+			continue
+		}
+
+		cursor := windowStart
+		for _, b := range busy {
+			if !b.End.After(cursor) || !b.Start.Before(windowEnd) {
+				continue
+			}
+			if b.Start.After(cursor) {
+				free = append(free, TimeSlot{Start: cursor, End: b.Start})
+			}
+			if b.End.After(cursor) {
+				cursor = roundUp(b.End, scheduleSlotGranularity)
+			}
+		}
+		if cursor.Before(windowEnd) {
+			free = append(free, TimeSlot{Start: cursor, End: windowEnd})
+		}
+	}
+	return free
+}
+
+// buildSchedule distribui as tasks sem StartDate nos slots livres, priorizando
+// prazo mais próximo e depois prioridade (first-fit).
+func buildSchedule(candidates []*Task, free []TimeSlot, fallbackMinutes int) ([]ScheduledTask, []UnscheduledTask) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.DueDate != nil && b.DueDate == nil:
+			return true
+		case a.DueDate == nil && b.DueDate != nil:
+			return false
+		case a.DueDate != nil && b.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Before(b.DueDate.Time)
+		}
+		if priorityWeight(a.Priority) != priorityWeight(b.Priority) {
+			return priorityWeight(a.Priority) < priorityWeight(b.Priority)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	scheduled := []ScheduledTask{}
+	unscheduled := []UnscheduledTask{}
+
+	for _, t := range candidates {
+		duration := taskEstimate(t, fallbackMinutes)
+		placed := false
+
+		for i := range free {
+			slot := &free[i]
+			end := slot.Start.Add(duration)
+			if end.After(slot.End) {
+				continue
+			}
+			if t.DueDate != nil && !t.DueDate.IsZero() && end.After(t.DueDate.Time) {
+				break
+			}
+
+			scheduled = append(scheduled, ScheduledTask{
+				TaskID:    t.ID,
+				Name:      t.Name,
+				Priority:  t.Priority,
+				StartDate: &util.LocalDateTime{Time: slot.Start},
+				EndDate:   &util.LocalDateTime{Time: end},
+				DueDate:   t.DueDate,
+			})
+			slot.Start = roundUp(end, scheduleSlotGranularity)
+			placed = true
+			break
+		}
+
+		if !placed {
+			reason := "no free slot within the scheduling horizon"
+			if t.DueDate != nil && !t.DueDate.IsZero() {
+				reason = "no free slot before due date"
+			}
+			unscheduled = append(unscheduled, UnscheduledTask{
+				TaskID: t.ID,
+				Name:   t.Name,
+				Reason: reason,
+			})
+		}
+	}
+
+	return scheduled, unscheduled
+}
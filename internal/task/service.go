@@ -3,14 +3,20 @@ package task
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"github.com/saulo-duarte/chronos-lambda/internal/flashcard"
+	"github.com/saulo-duarte/chronos-lambda/internal/milestone"
 	"github.com/saulo-duarte/chronos-lambda/internal/project"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
 	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
 	"github.com/saulo-duarte/chronos-lambda/internal/user"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,6 +26,9 @@ var (
 	ErrProjectNotFound    = project.ErrProjectNotFound
 	ErrStudyTopicNotFound = studytopic.ErrStudyTopicNotFound
 	ErrInvalidID          = errors.New("invalid id format")
+	ErrMilestoneNotFound  = milestone.ErrMilestoneNotFound
+	ErrForbidden          = project.ErrForbidden
+	ErrInvalidAssignee    = errors.New("assignee must be a member of the task project")
 )
 
 type TaskService interface {
@@ -30,21 +39,40 @@ type TaskService interface {
 	FindAllByProjectID(ctx context.Context, projectID string) ([]*Task, error)
 	FindAllByTopicID(ctx context.Context, topicID string) ([]*Task, error)
 	UpdateTask(ctx context.Context, t *Task) (*Task, error)
+	PreviewSchedule(ctx context.Context, prefs *SchedulePreferences) (*SchedulePlan, error)
+	ApplySchedule(ctx context.Context, prefs *SchedulePreferences) (*SchedulePlan, error)
+	AddDependency(ctx context.Context, taskID, dependsOnID string) (*TaskDependency, error)
+	RemoveDependency(ctx context.Context, taskID, dependsOnID string) error
+	GetProjectTimeline(ctx context.Context, projectID string) (*ProjectTimeline, error)
+	CloneProject(ctx context.Context, projectID string, opts CloneOptions) (*ProjectTree, error)
+	CloneStudySubject(ctx context.Context, subjectID string, opts CloneOptions) (*SubjectTree, error)
+	ListDueReviews(ctx context.Context, createTasks bool) (*DueReviews, error)
+	CreateStudyPlan(ctx context.Context, subjectID string, req *StudyPlanRequest, preview bool) (*StudyPlan, error)
+	GetStudyPlan(ctx context.Context, planID string) (*StudyPlan, error)
+	ReplanStudyPlan(ctx context.Context, planID string, preview bool) (*StudyPlan, error)
 }
 
 type taskService struct {
-	repo           TaskRepository
-	projectService project.ProjectService
-	userRepo       user.UserRepository
-	studyTopicRepo studytopic.StudyTopicRepository
+	repo             TaskRepository
+	projectService   project.ProjectService
+	userRepo         user.UserRepository
+	studyTopicRepo   studytopic.StudyTopicRepository
+	studySubjectRepo studysubject.StudySubjectRepository
+	milestoneRepo    milestone.MilestoneRepository
+	flashcardRepo    flashcard.FlashcardRepository
+	busyProvider     BusySlotProvider
 }
 
-func NewService(repo TaskRepository, projectService project.ProjectService, userRepo user.UserRepository, studyTopicRepo studytopic.StudyTopicRepository) TaskService {
+func NewService(repo TaskRepository, projectService project.ProjectService, userRepo user.UserRepository, studyTopicRepo studytopic.StudyTopicRepository, studySubjectRepo studysubject.StudySubjectRepository, milestoneRepo milestone.MilestoneRepository, flashcardRepo flashcard.FlashcardRepository, busyProvider BusySlotProvider) TaskService {
 	return &taskService{
-		repo:           repo,
-		projectService: projectService,
-		userRepo:       userRepo,
-		studyTopicRepo: studyTopicRepo,
+		repo:             repo,
+		projectService:   projectService,
+		userRepo:         userRepo,
+		studyTopicRepo:   studyTopicRepo,
+		studySubjectRepo: studySubjectRepo,
+		milestoneRepo:    milestoneRepo,
+		flashcardRepo:    flashcardRepo,
+		busyProvider:     busyProvider,
 	}
 }
 
@@ -72,15 +100,30 @@ func (s *taskService) validateTaskDependencies(ctx context.Context, log logrus.F
 	}
 
 	if t.ProjectId != nil {
-		if _, err := s.projectService.GetProjectByID(ctx, t.ProjectId.String()); err != nil {
+		if _, err := s.projectService.AuthorizeProject(ctx, t.ProjectId.String(), project.ROLE_EDITOR); err != nil {
+			if errors.Is(err, ErrForbidden) {
+				return err
+			}
 			log.WithError(err).WithFields(logrus.Fields{
 				"project_id": t.ProjectId,
 				"user_id":    t.UserID,
-			}).Error("Project not found or does not belong to the user")
+			}).Error("Project not found or user is not a member")
 			return ErrProjectNotFound
 		}
 	}
 
+	if t.AssigneeId != nil {
+		if err := s.validateAssignee(ctx, log, t.AssigneeId, t.ProjectId); err != nil {
+			return err
+		}
+	}
+
+	if t.MilestoneId != nil {
+		if err := s.validateMilestone(log, t.MilestoneId, t.ProjectId); err != nil {
+			return err
+		}
+	}
+
 	if t.StudyTopicId != nil {
 		if _, err := s.studyTopicRepo.GetByID(t.StudyTopicId.String()); err != nil {
 			log.WithError(err).WithFields(logrus.Fields{
@@ -94,6 +137,114 @@ func (s *taskService) validateTaskDependencies(ctx context.Context, log logrus.F
 	return nil
 }
 
+// warnUnmetPrerequisites avisa, sem bloquear, quando uma task STUDY em aberto é de um
+// tópico cujos pré-requisitos ainda não foram dominados.
+func (s *taskService) warnUnmetPrerequisites(log logrus.FieldLogger, t *Task) {
+	if t.Type != STUDY || t.Status == DONE || t.StudyTopicId == nil {
+		return
+	}
+
+	prerequisites, err := s.studyTopicRepo.ListPrerequisiteTopics(*t.StudyTopicId)
+	if err != nil {
+		log.WithError(err).Warn("Failed to check study topic prerequisites")
+		return
+	}
+
+	var names []string
+	for _, p := range prerequisites {
+		if p.Mastery == studytopic.MASTERED {
+			continue
+		}
+		t.UnmetPrerequisites = append(t.UnmetPrerequisites, p.ID)
+		names = append(names, p.Name)
+	}
+	if len(names) > 0 {
+		t.Warning = "prerequisites not mastered yet: " + strings.Join(names, ", ")
+	}
+}
+
+// topicsInLearningOrder lista os tópicos da matéria com os pré-requisitos antes de quem depende deles.
+func (s *taskService) topicsInLearningOrder(log logrus.FieldLogger, subjectID string, userID uuid.UUID) ([]*studytopic.StudyTopic, error) {
+	topics, err := s.studyTopicRepo.ListBySubject(subjectID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list topics for study plan")
+		return nil, err
+	}
+	edges, err := s.studyTopicRepo.ListPrerequisitesByUser(userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list topic prerequisites for study plan")
+		return nil, err
+	}
+	return studytopic.OrderByPrerequisites(topics, edges)
+}
+
+// validateMilestone garante que o marco existe e pertence ao projeto da task.
+func (s *taskService) validateMilestone(log logrus.FieldLogger, milestoneID, projectID *uuid.UUID) error {
+	m, err := s.milestoneRepo.GetByID(milestoneID.String())
+	if err != nil {
+		log.WithError(err).Error("Error finding milestone by ID")
+		return err
+	}
+	if m == nil || projectID == nil || m.ProjectID != *projectID {
+		log.WithFields(logrus.Fields{
+			"milestone_id": *milestoneID,
+			"project_id":   projectID,
+		}).Warn("Milestone not found or does not belong to the task project")
+		return ErrMilestoneNotFound
+	}
+	return nil
+}
+
+// validateAssignee só aceita responsáveis que sejam membros do projeto da task.
+func (s *taskService) validateAssignee(ctx context.Context, log logrus.FieldLogger, assigneeID, projectID *uuid.UUID) error {
+	if projectID == nil {
+		return ErrInvalidAssignee
+	}
+
+	ok, err := s.projectService.HasMember(ctx, *projectID, *assigneeID)
+	if err != nil {
+		log.WithError(err).Error("Error checking project membership for assignee")
+		return err
+	}
+	if !ok {
+		log.WithFields(logrus.Fields{
+			"assignee_id": *assigneeID,
+			"project_id":  *projectID,
+		}).Warn("Assignee is not a member of the task project")
+		return ErrInvalidAssignee
+	}
+	return nil
+}
+
+// findAuthorizedTask devolve a task se o usuário for o autor, o responsável ou
+// membro do projeto com pelo menos minRole. Tasks inacessíveis são tratadas como inexistentes.
+func (s *taskService) findAuthorizedTask(ctx context.Context, taskID, userID uuid.UUID, minRole project.MemberRole) (*Task, error) {
+	t, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// o responsável não é dono da task, só ganha acesso a ela; por isso a comparação direta
+	if auth.IsOwner(userID.String(), t.UserID) || (t.AssigneeId != nil && *t.AssigneeId == userID) {
+		return t, nil
+	}
+
+	if t.ProjectId == nil {
+		return nil, ErrNotFound
+	}
+
+	if _, err := s.projectService.AuthorizeProject(ctx, t.ProjectId.String(), minRole); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return nil, err
+		}
+		if errors.Is(err, project.ErrUnauthorized) || errors.Is(err, project.ErrProjectNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
 func (s *taskService) CreateTask(ctx context.Context, t *Task) (*Task, error) {
 	log := config.WithContext(ctx)
 	userID, err := getUserIDFromContext(ctx, log, "create task")
@@ -105,6 +256,8 @@ func (s *taskService) CreateTask(ctx context.Context, t *Task) (*Task, error) {
 	t.CreatedAt = time.Now()
 	t.UpdatedAt = time.Now()
 	t.UserID = userID
+	t.StudyPlanId = nil
+	t.AssessmentId = nil
 
 	if err := s.validateTaskDependencies(ctx, log, t); err != nil {
 		return nil, err
@@ -115,6 +268,8 @@ func (s *taskService) CreateTask(ctx context.Context, t *Task) (*Task, error) {
 		return nil, err
 	}
 
+	s.warnUnmetPrerequisites(log, t)
+
 	log.WithField("task_id", t.ID).Info("Task created successfully")
 	return t, nil
 }
@@ -146,7 +301,7 @@ func (s *taskService) FindByID(ctx context.Context, id string) (*Task, error) {
 		return nil, errors.New("invalid task id")
 	}
 
-	task, err := s.repo.FindByIdAndUserId(taskID, userID)
+	task, err := s.findAuthorizedTask(ctx, taskID, userID, project.ROLE_VIEWER)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
 			log.WithFields(logrus.Fields{
@@ -173,7 +328,7 @@ func (s *taskService) DeleteByID(ctx context.Context, id string) error {
 		return errors.New("invalid task id")
 	}
 
-	if _, err := s.repo.FindByIdAndUserId(taskID, userID); err != nil {
+	if _, err := s.findAuthorizedTask(ctx, taskID, userID, project.ROLE_EDITOR); err != nil {
 		if errors.Is(err, ErrNotFound) {
 			log.WithFields(logrus.Fields{
 				"task_id": id,
@@ -185,7 +340,7 @@ func (s *taskService) DeleteByID(ctx context.Context, id string) error {
 		return err
 	}
 
-	if err := s.repo.Delete(taskID, userID); err != nil {
+	if err := s.repo.Delete(taskID); err != nil {
 		if errors.Is(err, ErrNotFound) {
 			return ErrTaskNotFound
 		}
@@ -210,18 +365,18 @@ func (s *taskService) FindAllByProjectID(ctx context.Context, projectID string)
 	}
 
 	if _, err := s.projectService.GetProjectByID(ctx, projectID); err != nil {
-		if errors.Is(err, project.ErrProjectNotFound) {
+		if errors.Is(err, project.ErrProjectNotFound) || errors.Is(err, project.ErrUnauthorized) {
 			log.WithFields(logrus.Fields{
 				"project_id": projectID,
 				"user_id":    userID,
-			}).Warn("Project not found or does not belong to user")
+			}).Warn("Project not found or user is not a member")
 			return nil, ErrProjectNotFound
 		}
 		log.WithError(err).Error("Error finding project by ID")
 		return nil, err
 	}
 
-	tasks, err := s.repo.ListByProjectAndUser(pid, userID)
+	tasks, err := s.repo.ListByProject(pid)
 	if err != nil {
 		log.WithError(err).Error("Failed to list tasks by project")
 		return nil, err
@@ -268,7 +423,7 @@ func (s *taskService) UpdateTask(ctx context.Context, t *Task) (*Task, error) {
 		return nil, err
 	}
 
-	existing, err := s.repo.FindByIdAndUserId(t.ID, userID)
+	existing, err := s.findAuthorizedTask(ctx, t.ID, userID, project.ROLE_EDITOR)
 	if err != nil {
 		if errors.Is(err, ErrNotFound) {
 			log.WithFields(logrus.Fields{
@@ -302,6 +457,24 @@ func (s *taskService) UpdateTask(ctx context.Context, t *Task) (*Task, error) {
 	if !t.DoneAt.IsZero() {
 		existing.DoneAt = t.DoneAt
 	}
+	if t.EstimatedMinutes > 0 {
+		existing.EstimatedMinutes = t.EstimatedMinutes
+	}
+	if t.LoggedMinutes > 0 {
+		existing.LoggedMinutes = t.LoggedMinutes
+	}
+	if t.AssigneeId != nil && (existing.AssigneeId == nil || *t.AssigneeId != *existing.AssigneeId) {
+		if err := s.validateAssignee(ctx, log, t.AssigneeId, existing.ProjectId); err != nil {
+			return nil, err
+		}
+		existing.AssigneeId = t.AssigneeId
+	}
+	if t.MilestoneId != nil && (existing.MilestoneId == nil || *t.MilestoneId != *existing.MilestoneId) {
+		if err := s.validateMilestone(log, t.MilestoneId, existing.ProjectId); err != nil {
+			return nil, err
+		}
+		existing.MilestoneId = t.MilestoneId
+	}
 
 	existing.UpdatedAt = time.Now()
 
@@ -310,6 +483,614 @@ func (s *taskService) UpdateTask(ctx context.Context, t *Task) (*Task, error) {
 		return nil, err
 	}
 
+	s.warnUnmetPrerequisites(log, existing)
+
 	log.WithField("task_id", existing.ID).Info("Task updated successfully")
 	return existing, nil
 }
+
+func (s *taskService) PreviewSchedule(ctx context.Context, prefs *SchedulePreferences) (*SchedulePlan, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "preview schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planSchedule(ctx, log, userID, prefs)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"user_id":     userID,
+		"scheduled":   len(plan.Scheduled),
+		"unscheduled": len(plan.Unscheduled),
+	}).Info("Schedule preview generated")
+	return plan, nil
+}
+
+func (s *taskService) ApplySchedule(ctx context.Context, prefs *SchedulePreferences) (*SchedulePlan, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "apply schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planSchedule(ctx, log, userID, prefs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ApplySchedule(userID, plan.Scheduled); err != nil {
+		if errors.Is(err, ErrScheduleConflict) {
+			log.WithField("user_id", userID).Warn("Schedule conflict while applying plan")
+			return nil, err
+		}
+		log.WithError(err).Error("Failed to apply schedule")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"scheduled": len(plan.Scheduled),
+	}).Info("Schedule applied successfully")
+	return plan, nil
+}
+
+func (s *taskService) planSchedule(ctx context.Context, log logrus.FieldLogger, userID uuid.UUID, prefs *SchedulePreferences) (*SchedulePlan, error) {
+	hours, from, until, err := prefs.normalize(util.LocalNow())
+	if err != nil {
+		log.WithError(err).Warn("Invalid schedule preferences")
+		return nil, err
+	}
+
+	tasks, err := s.repo.ListOpenByUser(userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list open tasks for scheduling")
+		return nil, err
+	}
+
+	selected := make(map[uuid.UUID]bool, len(prefs.TaskIDs))
+	for _, id := range prefs.TaskIDs {
+		selected[id] = true
+	}
+
+	var candidates []*Task
+	for _, t := range tasks {
+		if t.StartDate != nil && !t.StartDate.IsZero() {
+			continue
+		}
+		if len(selected) > 0 && !selected[t.ID] {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+
+	busy := busyFromTasks(tasks, prefs.DefaultEstimateMinutes)
+	if prefs.IncludeGoogleCalendar {
+		busy = append(busy, s.googleBusySlots(ctx, log, userID, from, until)...)
+	}
+
+	scheduled, unscheduled := buildSchedule(candidates, freeSlots(hours, from, until, busy), prefs.DefaultEstimateMinutes)
+
+	return &SchedulePlan{
+		From:        &util.LocalDateTime{Time: from},
+		Until:       &util.LocalDateTime{Time: until},
+		Scheduled:   scheduled,
+		Unscheduled: unscheduled,
+	}, nil
+}
+
+// googleBusySlots é best-effort: falhas no Google Calendar não impedem o planejamento.
+func (s *taskService) googleBusySlots(ctx context.Context, log logrus.FieldLogger, userID uuid.UUID, from, until time.Time) []TimeSlot {
+	if s.busyProvider == nil {
+		return nil
+	}
+
+	encrypted, err := s.userRepo.GetUserEncryptedGoogleCalendarAccessToken(userID.String())
+	if err != nil || encrypted == "" {
+		log.WithError(err).WithField("user_id", userID).Warn("Google access token unavailable, ignoring calendar busy blocks")
+		return nil
+	}
+
+	accessToken, err := config.Decrypt(encrypted)
+	if err != nil {
+		log.WithError(err).Warn("Failed to decrypt Google access token")
+		return nil
+	}
+
+	slots, err := s.busyProvider.BusySlots(ctx, accessToken, from, until)
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch Google Calendar busy blocks")
+		return nil
+	}
+	return slots
+}
+
+func (s *taskService) AddDependency(ctx context.Context, taskID, dependsOnID string) (*TaskDependency, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "add task dependency")
+	if err != nil {
+		return nil, err
+	}
+
+	tid, err := parseUUID(log, taskID, "task")
+	if err != nil {
+		return nil, err
+	}
+	did, err := parseUUID(log, dependsOnID, "task")
+	if err != nil {
+		return nil, err
+	}
+
+	t, dependsOn, err := s.loadDependencyPair(ctx, tid, did, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := s.repo.ListDependenciesByProject(*t.ProjectId)
+	if err != nil {
+		log.WithError(err).Error("Failed to list project dependencies")
+		return nil, err
+	}
+	for _, d := range deps {
+		if d.TaskID == t.ID && d.DependsOnID == dependsOn.ID {
+			return &d, nil
+		}
+	}
+	if createsCycle(deps, t.ID, dependsOn.ID) {
+		log.WithFields(logrus.Fields{
+			"task_id":       t.ID,
+			"depends_on_id": dependsOn.ID,
+		}).Warn("Dependency rejected because it would create a cycle")
+		return nil, ErrDependencyCycle
+	}
+
+	dep := &TaskDependency{TaskID: t.ID, DependsOnID: dependsOn.ID, CreatedAt: time.Now()}
+	if err := s.repo.AddDependency(dep); err != nil {
+		log.WithError(err).Error("Failed to add task dependency")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"task_id":       t.ID,
+		"depends_on_id": dependsOn.ID,
+	}).Info("Task dependency added successfully")
+	return dep, nil
+}
+
+func (s *taskService) RemoveDependency(ctx context.Context, taskID, dependsOnID string) error {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "remove task dependency")
+	if err != nil {
+		return err
+	}
+
+	tid, err := parseUUID(log, taskID, "task")
+	if err != nil {
+		return err
+	}
+	did, err := parseUUID(log, dependsOnID, "task")
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.findAuthorizedTask(ctx, tid, userID, project.ROLE_EDITOR); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrTaskNotFound
+		}
+		return err
+	}
+
+	if err := s.repo.RemoveDependency(tid, did); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrTaskNotFound
+		}
+		log.WithError(err).Error("Failed to remove task dependency")
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		"task_id":       tid,
+		"depends_on_id": did,
+	}).Info("Task dependency removed successfully")
+	return nil
+}
+
+// loadDependencyPair carrega as duas tasks e exige que sejam distintas e do mesmo projeto.
+func (s *taskService) loadDependencyPair(ctx context.Context, taskID, dependsOnID, userID uuid.UUID) (*Task, *Task, error) {
+	if taskID == dependsOnID {
+		return nil, nil, ErrInvalidDependency
+	}
+
+	t, err := s.findAuthorizedTask(ctx, taskID, userID, project.ROLE_EDITOR)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrTaskNotFound
+		}
+		return nil, nil, err
+	}
+
+	dependsOn, err := s.findAuthorizedTask(ctx, dependsOnID, userID, project.ROLE_VIEWER)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrTaskNotFound
+		}
+		return nil, nil, err
+	}
+
+	if t.ProjectId == nil || dependsOn.ProjectId == nil || *t.ProjectId != *dependsOn.ProjectId {
+		return nil, nil, ErrInvalidDependency
+	}
+	return t, dependsOn, nil
+}
+
+func (s *taskService) GetProjectTimeline(ctx context.Context, projectID string) (*ProjectTimeline, error) {
+	log := config.WithContext(ctx)
+
+	p, err := s.projectService.GetProjectByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, project.ErrUnauthorized) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	tasks, err := s.repo.ListByProject(p.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list tasks for project timeline")
+		return nil, err
+	}
+
+	deps, err := s.repo.ListDependenciesByProject(p.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list dependencies for project timeline")
+		return nil, err
+	}
+
+	milestones, err := s.milestoneRepo.ListByProject(p.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list milestones for project timeline")
+		return nil, err
+	}
+
+	timeline, err := buildTimeline(p.ID, tasks, deps, milestones, util.LocalNow())
+	if err != nil {
+		log.WithError(err).WithField("project_id", p.ID).Error("Failed to compute project timeline")
+		return nil, err
+	}
+	return timeline, nil
+}
+
+func (s *taskService) CloneProject(ctx context.Context, projectID string, opts CloneOptions) (*ProjectTree, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "clone project")
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	p, err := s.projectService.GetProjectByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, project.ErrUnauthorized) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	src := &ProjectTree{Project: p}
+	if src.Milestones, err = s.milestoneRepo.ListByProject(p.ID); err != nil {
+		log.WithError(err).Error("Failed to list milestones for project clone")
+		return nil, err
+	}
+	if src.Tasks, err = s.repo.ListByProject(p.ID); err != nil {
+		log.WithError(err).Error("Failed to list tasks for project clone")
+		return nil, err
+	}
+	if src.Dependencies, err = s.repo.ListDependenciesByProject(p.ID); err != nil {
+		log.WithError(err).Error("Failed to list dependencies for project clone")
+		return nil, err
+	}
+
+	tree := cloneProjectTree(src, userID, opts, time.Now())
+	if err := s.repo.CreateProjectTree(tree); err != nil {
+		log.WithError(err).Error("Failed to persist cloned project")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"source_project_id": p.ID,
+		"project_id":        tree.Project.ID,
+		"tasks":             len(tree.Tasks),
+		"milestones":        len(tree.Milestones),
+	}).Info("Project cloned successfully")
+	return tree, nil
+}
+
+func (s *taskService) CloneStudySubject(ctx context.Context, subjectID string, opts CloneOptions) (*SubjectTree, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "clone study subject")
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	subject, err := s.getOwnedSubject(log, subjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &SubjectTree{Subject: subject}
+	if src.Topics, err = s.studyTopicRepo.ListBySubject(subjectID); err != nil {
+		log.WithError(err).Error("Failed to list topics for study subject clone")
+		return nil, err
+	}
+
+	topicIDs := make([]uuid.UUID, 0, len(src.Topics))
+	for _, t := range src.Topics {
+		topicIDs = append(topicIDs, t.ID)
+	}
+	if src.Tasks, err = s.repo.ListByStudyTopics(topicIDs, userID); err != nil {
+		log.WithError(err).Error("Failed to list tasks for study subject clone")
+		return nil, err
+	}
+	if src.Flashcards, err = s.flashcardRepo.ListByTopics(topicIDs); err != nil {
+		log.WithError(err).Error("Failed to list flashcards for study subject clone")
+		return nil, err
+	}
+	if src.Prerequisites, err = s.studyTopicRepo.ListPrerequisitesByUser(userID); err != nil {
+		log.WithError(err).Error("Failed to list topic prerequisites for study subject clone")
+		return nil, err
+	}
+	if src.Resources, err = s.studyTopicRepo.ListResourcesByTopics(topicIDs); err != nil {
+		log.WithError(err).Error("Failed to list resources for study subject clone")
+		return nil, err
+	}
+
+	tree := cloneSubjectTree(src, userID, opts, time.Now())
+	// a cópia costuma ser para o próximo período, então entra no período ativo
+	active, err := s.studySubjectRepo.ActiveTerm(userID, util.LocalNow())
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch active term for study subject clone")
+		return nil, err
+	}
+	if active != nil {
+		tree.Subject.TermID = &active.ID
+	}
+	if err := s.repo.CreateSubjectTree(tree); err != nil {
+		log.WithError(err).Error("Failed to persist cloned study subject")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"source_subject_id": subject.ID,
+		"subject_id":        tree.Subject.ID,
+		"topics":            len(tree.Topics),
+		"tasks":             len(tree.Tasks),
+		"flashcards":        len(tree.Flashcards),
+		"resources":         len(tree.Resources),
+	}).Info("Study subject cloned successfully")
+	return tree, nil
+}
+
+// ListDueReviews lista os tópicos com revisão vencida até hoje e, se pedido,
+// cria uma task STUDY para cada tópico que ainda não tenha uma em aberto.
+func (s *taskService) ListDueReviews(ctx context.Context, createTasks bool) (*DueReviews, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "list due reviews")
+	if err != nil {
+		return nil, err
+	}
+
+	// o corte é o fim do dia local, como o next_review_at
+	now := time.Now()
+	today := util.LocalNow()
+	tomorrow := startOfDay(today).AddDate(0, 0, 1)
+
+	due, err := s.studyTopicRepo.ListDueReviews(userID, tomorrow)
+	if err != nil {
+		log.WithError(err).Error("Failed to list due reviews")
+		return nil, err
+	}
+
+	result := &DueReviews{Reviews: due, CreatedTasks: []*Task{}}
+	if !createTasks || len(due) == 0 {
+		return result, nil
+	}
+
+	openTasks, err := s.repo.ListOpenByUser(userID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list open tasks for due reviews")
+		return nil, err
+	}
+	open := openStudyTopics(openTasks)
+
+	for _, d := range due {
+		if open[d.Topic.ID] {
+			continue
+		}
+		t := reviewTask(d, userID, today, now)
+		if err := s.repo.Create(t); err != nil {
+			log.WithError(err).WithField("topic_id", d.Topic.ID).Error("Failed to create review task")
+			return nil, err
+		}
+		open[d.Topic.ID] = true
+		result.CreatedTasks = append(result.CreatedTasks, t)
+	}
+
+	log.WithFields(logrus.Fields{
+		"user_id":       userID,
+		"due":           len(due),
+		"created_tasks": len(result.CreatedTasks),
+	}).Info("Due reviews listed successfully")
+	return result, nil
+}
+
+// getOwnedSubject busca a matéria e trata matérias de outros usuários como inexistentes.
+func (s *taskService) getOwnedSubject(log logrus.FieldLogger, subjectID string, userID uuid.UUID) (*studysubject.StudySubject, error) {
+	if _, err := parseUUID(log, subjectID, "study subject"); err != nil {
+		return nil, err
+	}
+
+	subject, err := s.studySubjectRepo.GetByID(subjectID)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch study subject")
+		return nil, err
+	}
+	if subject == nil || !auth.IsOwner(userID.String(), subject.UserID) {
+		return nil, ErrStudySubjectNotFound
+	}
+	return subject, nil
+}
+
+// CreateStudyPlan gera as sessões de estudo e revisão da matéria até a prova. Com preview,
+// devolve o plano sem gravar nada.
+func (s *taskService) CreateStudyPlan(ctx context.Context, subjectID string, req *StudyPlanRequest, preview bool) (*StudyPlan, error) {
+	log := config.WithContext(ctx)
+	userID, err := getUserIDFromContext(ctx, log, "create study plan")
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := req.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := s.getOwnedSubject(log, subjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.topicsInLearningOrder(log, subjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("%w: subject has no topics", ErrInvalidStudyPlan)
+	}
+
+	// o calendário compara from com o horário local das sessões
+	now := time.Now()
+	from := util.LocalNow()
+	if req.From != nil && !req.From.IsZero() {
+		from = req.From.Time
+	}
+	if !startOfDay(from).Before(startOfDay(plan.ExamDate.Time)) {
+		return nil, fmt.Errorf("%w: examDate must be after the plan start", ErrInvalidStudyPlan)
+	}
+
+	plan.ID = uuid.New()
+	plan.SubjectID = subject.ID
+	plan.UserID = userID
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+
+	plan.Tasks = materializePlan(plan, topics, nil, from, now)
+	if preview {
+		return plan, nil
+	}
+
+	if err := s.repo.CreateStudyPlan(plan, plan.Tasks); err != nil {
+		log.WithError(err).Error("Failed to persist study plan")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"plan_id":    plan.ID,
+		"subject_id": subject.ID,
+		"tasks":      len(plan.Tasks),
+	}).Info("Study plan created successfully")
+	return plan, nil
+}
+
+func (s *taskService) getOwnedStudyPlan(ctx context.Context, log logrus.FieldLogger, planID, action string) (*StudyPlan, error) {
+	userID, err := getUserIDFromContext(ctx, log, action)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseUUID(log, planID, "study plan")
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.repo.GetStudyPlan(id)
+	if err != nil {
+		log.WithError(err).Error("Failed to fetch study plan")
+		return nil, err
+	}
+	if plan == nil || !auth.IsOwner(userID.String(), plan.UserID) {
+		return nil, ErrStudyPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *taskService) GetStudyPlan(ctx context.Context, planID string) (*StudyPlan, error) {
+	log := config.WithContext(ctx)
+
+	plan, err := s.getOwnedStudyPlan(ctx, log, planID, "get study plan")
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Tasks, err = s.repo.ListByStudyPlan(plan.ID); err != nil {
+		log.WithError(err).Error("Failed to list study plan tasks")
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ReplanStudyPlan redistribui, a partir de agora, o estudo ainda não concluído e refaz as
+// passadas de revisão nos dias que restam até a prova.
+func (s *taskService) ReplanStudyPlan(ctx context.Context, planID string, preview bool) (*StudyPlan, error) {
+	log := config.WithContext(ctx)
+
+	plan, err := s.getOwnedStudyPlan(ctx, log, planID, "replan study plan")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := util.LocalNow()
+	if !startOfDay(today).Before(startOfDay(plan.ExamDate.Time)) {
+		return nil, fmt.Errorf("%w: the exam date has already passed", ErrInvalidStudyPlan)
+	}
+
+	tasks, err := s.repo.ListByStudyPlan(plan.ID)
+	if err != nil {
+		log.WithError(err).Error("Failed to list study plan tasks")
+		return nil, err
+	}
+	topics, err := s.topicsInLearningOrder(log, plan.SubjectID.String(), plan.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := pendingStudyDemands(plan, topics, tasks)
+	fresh := materializePlan(plan, topics, pending, today, now)
+	plan.UpdatedAt = now
+
+	plan.Tasks = fresh
+	for _, t := range tasks {
+		if t.Status == DONE {
+			plan.Tasks = append(plan.Tasks, t)
+		}
+	}
+	if preview {
+		return plan, nil
+	}
+
+	if err := s.repo.ReplanStudyPlan(plan, fresh); err != nil {
+		log.WithError(err).Error("Failed to replan study plan")
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"plan_id": plan.ID,
+		"tasks":   len(fresh),
+	}).Info("Study plan replanned successfully")
+	return plan, nil
+}
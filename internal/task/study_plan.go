@@ -0,0 +1,385 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+const (
+	defaultStudyMinutesPerDay = 120
+	maxStudyMinutesPerDay     = 720
+	defaultStudySessionStart  = "19:00"
+	defaultReviewPasses       = 1
+	maxReviewPasses           = 5
+	minStudySessionMinutes    = 15
+	studyTaskPrefix           = "Estudo: "
+)
+
+var (
+	ErrStudyPlanNotFound = errors.New("study plan not found")
+	ErrInvalidStudyPlan  = errors.New("invalid study plan")
+)
+
+// StudyPlan guarda os parâmetros usados para gerar as tasks STUDY de uma matéria até a prova.
+type StudyPlan struct {
+	ID           uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4()" json:"id"`
+	SubjectID    uuid.UUID             `gorm:"column:subject_id;not null;index" json:"subjectId"`
+	UserID       uuid.UUID             `gorm:"column:user_id;not null" json:"userId"`
+	ExamDate     *util.LocalDateTime   `json:"examDate"`
+	ReviewStart  *util.LocalDateTime   `json:"reviewStart"`
+	DailyMinutes int                   `json:"dailyMinutes"`
+	SessionStart string                `json:"sessionStart"`
+	StudyDays    []time.Weekday        `gorm:"serializer:json" json:"studyDays"`
+	ReviewPasses int                   `json:"reviewPasses"`
+	ReviewDays   int                   `json:"reviewDays"`
+	Weights      map[uuid.UUID]float64 `gorm:"serializer:json" json:"weights"`
+	CreatedAt    time.Time             `json:"createdAt"`
+	UpdatedAt    time.Time             `json:"updatedAt"`
+	Warning      string                `gorm:"-" json:"warning,omitempty"`
+	Tasks        []*Task               `gorm:"-" json:"tasks"`
+}
+
+type StudyPlanRequest struct {
+	ExamDate     *util.LocalDateTime   `json:"examDate"`
+	From         *util.LocalDateTime   `json:"from"`
+	DailyMinutes int                   `json:"dailyMinutes"`
+	SessionStart string                `json:"sessionStart"`
+	StudyDays    []time.Weekday        `json:"studyDays"`
+	ReviewPasses *int                  `json:"reviewPasses"`
+	ReviewDays   int                   `json:"reviewDays"`
+	Weights      map[uuid.UUID]float64 `json:"weights"`
+}
+
+// normalize aplica os padrões e valida a requisição, devolvendo o plano ainda sem tasks.
+func (r *StudyPlanRequest) normalize() (*StudyPlan, error) {
+	if r.ExamDate == nil || r.ExamDate.IsZero() {
+		return nil, fmt.Errorf("%w: examDate is required", ErrInvalidStudyPlan)
+	}
+
+	plan := &StudyPlan{
+		ExamDate:     r.ExamDate,
+		DailyMinutes: r.DailyMinutes,
+		SessionStart: r.SessionStart,
+		StudyDays:    r.StudyDays,
+		ReviewPasses: defaultReviewPasses,
+		ReviewDays:   r.ReviewDays,
+		Weights:      r.Weights,
+	}
+	if plan.DailyMinutes == 0 {
+		plan.DailyMinutes = defaultStudyMinutesPerDay
+	}
+	if plan.SessionStart == "" {
+		plan.SessionStart = defaultStudySessionStart
+	}
+	if len(plan.StudyDays) == 0 {
+		plan.StudyDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	}
+	if r.ReviewPasses != nil {
+		plan.ReviewPasses = *r.ReviewPasses
+	}
+	if plan.Weights == nil {
+		plan.Weights = map[uuid.UUID]float64{}
+	}
+
+	if plan.DailyMinutes < minStudySessionMinutes || plan.DailyMinutes > maxStudyMinutesPerDay {
+		return nil, fmt.Errorf("%w: dailyMinutes must be between %d and %d", ErrInvalidStudyPlan, minStudySessionMinutes, maxStudyMinutesPerDay)
+	}
+	plan.DailyMinutes -= plan.DailyMinutes % minStudySessionMinutes
+
+	start, err := parseClock(plan.SessionStart)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sessionStart must use HH:MM format", ErrInvalidStudyPlan)
+	}
+	if start+time.Duration(plan.DailyMinutes)*time.Minute > 24*time.Hour {
+		return nil, fmt.Errorf("%w: daily sessions cannot cross midnight", ErrInvalidStudyPlan)
+	}
+	for _, d := range plan.StudyDays {
+		if d < time.Sunday || d > time.Saturday {
+			return nil, fmt.Errorf("%w: invalid study day %d", ErrInvalidStudyPlan, d)
+		}
+	}
+	if plan.ReviewPasses < 0 || plan.ReviewPasses > maxReviewPasses {
+		return nil, fmt.Errorf("%w: reviewPasses must be between 0 and %d", ErrInvalidStudyPlan, maxReviewPasses)
+	}
+	if plan.ReviewDays < 0 {
+		return nil, fmt.Errorf("%w: reviewDays cannot be negative", ErrInvalidStudyPlan)
+	}
+	for id, w := range plan.Weights {
+		if w <= 0 {
+			return nil, fmt.Errorf("%w: weight for topic %s must be positive", ErrInvalidStudyPlan, id)
+		}
+	}
+	return plan, nil
+}
+
+func (p *StudyPlan) weight(topicID uuid.UUID) float64 {
+	if w, ok := p.Weights[topicID]; ok {
+		return w
+	}
+	return 1
+}
+
+// studyDemand é o tempo que um tópico precisa numa fase do plano (estudo ou uma passada de revisão).
+type studyDemand struct {
+	topic   *studytopic.StudyTopic
+	review  bool
+	pass    int
+	minutes int
+}
+
+type studySession struct {
+	demand  studyDemand
+	start   time.Time
+	minutes int
+}
+
+// planCalendar separa os dias disponíveis antes da prova em janela de estudo e janela de revisão.
+type planCalendar struct {
+	studyDays  []time.Time
+	reviewDays []time.Time
+}
+
+// buildCalendar lista os dias de estudo entre from e o dia anterior à prova. O dia de from só
+// entra se o horário da sessão ainda não passou.
+func buildCalendar(plan *StudyPlan, from time.Time) planCalendar {
+	sessionStart, _ := parseClock(plan.SessionStart)
+	allowed := make(map[time.Weekday]bool, len(plan.StudyDays))
+	for _, d := range plan.StudyDays {
+		allowed[d] = true
+	}
+
+	first := startOfDay(from)
+	if from.After(first.Add(sessionStart)) {
+		first = first.AddDate(0, 0, 1)
+	}
+	exam := startOfDay(plan.ExamDate.Time)
+
+	var days []time.Time
+	for day := first; day.Before(exam); day = day.AddDate(0, 0, 1) {
+		if allowed[day.Weekday()] {
+			days = append(days, day)
+		}
+	}
+
+	reviewDays := 0
+	if plan.ReviewPasses > 0 && len(days) > 1 {
+		reviewDays = plan.ReviewDays
+		if reviewDays == 0 {
+			reviewDays = int(math.Ceil(float64(len(days)) / 5))
+		}
+		if reviewDays > len(days)-1 {
+			reviewDays = len(days) - 1
+		}
+	}
+
+	split := len(days) - reviewDays
+	return planCalendar{studyDays: days[:split], reviewDays: days[split:]}
+}
+
+func roundDownSession(minutes float64) int {
+	m := int(minutes) - int(minutes)%minStudySessionMinutes
+	if m < minStudySessionMinutes {
+		return minStudySessionMinutes
+	}
+	return m
+}
+
+// shareDemands divide capacity entre os tópicos proporcionalmente aos pesos, na ordem de Position.
+func shareDemands(plan *StudyPlan, topics []*studytopic.StudyTopic, capacity int, review bool, pass int) []studyDemand {
+	var total float64
+	for _, t := range topics {
+		total += plan.weight(t.ID)
+	}
+
+	demands := make([]studyDemand, 0, len(topics))
+	for _, t := range topics {
+		demands = append(demands, studyDemand{
+			topic:   t,
+			review:  review,
+			pass:    pass,
+			minutes: roundDownSession(float64(capacity) * plan.weight(t.ID) / total),
+		})
+	}
+	return demands
+}
+
+func reviewDemands(plan *StudyPlan, topics []*studytopic.StudyTopic, capacity int) []studyDemand {
+	if plan.ReviewPasses == 0 || capacity == 0 {
+		return nil
+	}
+	var demands []studyDemand
+	perPass := capacity / plan.ReviewPasses
+	for pass := 1; pass <= plan.ReviewPasses; pass++ {
+		demands = append(demands, shareDemands(plan, topics, perPass, true, pass)...)
+	}
+	return demands
+}
+
+// fitDemands reduz proporcionalmente as demandas que não cabem na capacidade.
+func fitDemands(demands []studyDemand, capacity int) ([]studyDemand, bool) {
+	total := 0
+	for _, d := range demands {
+		total += d.minutes
+	}
+	if total <= capacity {
+		return demands, false
+	}
+
+	ratio := float64(capacity) / float64(total)
+	fitted := make([]studyDemand, len(demands))
+	for i, d := range demands {
+		d.minutes = roundDownSession(float64(d.minutes) * ratio)
+		fitted[i] = d
+	}
+	return fitted, true
+}
+
+// allocateSessions preenche os dias em sequência, quebrando um tópico em várias sessões quando
+// ele não cabe no restante do dia. Devolve os minutos que não couberam.
+func allocateSessions(demands []studyDemand, days []time.Time, dailyMinutes int, sessionStart time.Duration) ([]studySession, int) {
+	var sessions []studySession
+	dayIdx, used, unplaced := 0, 0, 0
+
+	for _, d := range demands {
+		remaining := d.minutes
+		for remaining > 0 && dayIdx < len(days) {
+			free := dailyMinutes - used
+			if free < minStudySessionMinutes {
+				dayIdx++
+				used = 0
+				continue
+			}
+			chunk := remaining
+			if chunk > free {
+				chunk = free
+			}
+			sessions = append(sessions, studySession{
+				demand:  d,
+				start:   days[dayIdx].Add(sessionStart + time.Duration(used)*time.Minute),
+				minutes: chunk,
+			})
+			used += chunk
+			remaining -= chunk
+		}
+		unplaced += remaining
+	}
+	return sessions, unplaced
+}
+
+func (s studySession) toTask(plan *StudyPlan, now time.Time) *Task {
+	topicID := s.demand.topic.ID
+	planID := plan.ID
+	end := s.start.Add(time.Duration(s.minutes) * time.Minute)
+
+	t := &Task{
+		ID:               uuid.New(),
+		Name:             studyTaskPrefix + s.demand.topic.Name,
+		Description:      "Sessão de estudo do plano até a prova",
+		Status:           TODO,
+		Type:             STUDY,
+		Priority:         MEDIUM,
+		StartDate:        &util.LocalDateTime{Time: s.start},
+		DueDate:          &util.LocalDateTime{Time: end},
+		EstimatedMinutes: s.minutes,
+		StudyTopicId:     &topicID,
+		StudyPlanId:      &planID,
+		UserID:           plan.UserID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if s.demand.review {
+		t.Name = reviewTaskPrefix + s.demand.topic.Name
+		t.Description = fmt.Sprintf("Revisão %d de %d antes da prova", s.demand.pass, plan.ReviewPasses)
+		t.Priority = HIGH
+	}
+	return t
+}
+
+// materializePlan distribui as demandas no calendário e gera as tasks. studyDemands nil
+// significa plano novo, em que todo o tempo de estudo é dividido pelos pesos.
+func materializePlan(plan *StudyPlan, topics []*studytopic.StudyTopic, studyDemands []studyDemand, from, now time.Time) []*Task {
+	cal := buildCalendar(plan, from)
+	sessionStart, _ := parseClock(plan.SessionStart)
+	studyCapacity := len(cal.studyDays) * plan.DailyMinutes
+	reviewCapacity := len(cal.reviewDays) * plan.DailyMinutes
+
+	plan.ReviewStart = nil
+	if len(cal.reviewDays) > 0 {
+		plan.ReviewStart = &util.LocalDateTime{Time: cal.reviewDays[0]}
+	}
+
+	if studyDemands == nil && studyCapacity > 0 {
+		studyDemands = shareDemands(plan, topics, studyCapacity, false, 0)
+	}
+
+	var warnings []string
+	studyDemands, shortened := fitDemands(studyDemands, studyCapacity)
+	if shortened {
+		warnings = append(warnings, "not enough study days before the exam; study sessions were shortened")
+	}
+	if studyCapacity == 0 && len(studyDemands) > 0 {
+		warnings = append(warnings, "no study days left before the exam")
+	}
+
+	reviews, shortened := fitDemands(reviewDemands(plan, topics, reviewCapacity), reviewCapacity)
+	if shortened {
+		warnings = append(warnings, "review window is too short for every topic; review sessions were shortened")
+	}
+
+	studySessions, unplacedStudy := allocateSessions(studyDemands, cal.studyDays, plan.DailyMinutes, sessionStart)
+	reviewSessions, unplacedReview := allocateSessions(reviews, cal.reviewDays, plan.DailyMinutes, sessionStart)
+	if unplacedStudy+unplacedReview > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d minutes could not be placed before the exam", unplacedStudy+unplacedReview))
+	}
+
+	tasks := make([]*Task, 0, len(studySessions)+len(reviewSessions))
+	for _, s := range append(studySessions, reviewSessions...) {
+		tasks = append(tasks, s.toTask(plan, now))
+	}
+
+	plan.Warning = ""
+	for i, w := range warnings {
+		if i > 0 {
+			plan.Warning += "; "
+		}
+		plan.Warning += w
+	}
+	return tasks
+}
+
+// pendingStudyDemands soma, por tópico e na ordem de Position, os minutos das sessões de estudo
+// ainda abertas, que precisam ser redistribuídas no replanejamento.
+func pendingStudyDemands(plan *StudyPlan, topics []*studytopic.StudyTopic, tasks []*Task) []studyDemand {
+	pending := make(map[uuid.UUID]int)
+	for _, t := range tasks {
+		if t.Status == DONE || t.StudyTopicId == nil || isReviewSession(plan, t) {
+			continue
+		}
+		pending[*t.StudyTopicId] += taskMinutes(t)
+	}
+
+	demands := []studyDemand{}
+	for _, topic := range topics {
+		if minutes := pending[topic.ID]; minutes > 0 {
+			demands = append(demands, studyDemand{topic: topic, minutes: minutes})
+		}
+	}
+	return demands
+}
+
+func isReviewSession(plan *StudyPlan, t *Task) bool {
+	return plan.ReviewStart != nil && t.StartDate != nil && !t.StartDate.Before(plan.ReviewStart.Time)
+}
+
+func taskMinutes(t *Task) int {
+	if t.EstimatedMinutes > 0 {
+		return t.EstimatedMinutes
+	}
+	return minStudySessionMinutes
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	studysubject "github.com/saulo-duarte/chronos-lambda/internal/study_subject"
+	studytopic "github.com/saulo-duarte/chronos-lambda/internal/study_topic"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+// Os fakes embutem a interface: só os métodos usados pelo plano de estudos são implementados,
+// e qualquer outra chamada falha o teste com panic.
+
+type fakePlanRepo struct {
+	TaskRepository
+	plans map[uuid.UUID]*StudyPlan
+	tasks map[uuid.UUID][]*Task
+}
+
+func (r *fakePlanRepo) GetStudyPlan(id uuid.UUID) (*StudyPlan, error) {
+	return r.plans[id], nil
+}
+
+func (r *fakePlanRepo) ListByStudyPlan(planID uuid.UUID) ([]*Task, error) {
+	return r.tasks[planID], nil
+}
+
+func (r *fakePlanRepo) CreateStudyPlan(plan *StudyPlan, tasks []*Task) error {
+	r.plans[plan.ID] = plan
+	r.tasks[plan.ID] = tasks
+	return nil
+}
+
+func (r *fakePlanRepo) ReplanStudyPlan(plan *StudyPlan, tasks []*Task) error {
+	r.tasks[plan.ID] = tasks
+	return nil
+}
+
+type fakeTopicRepo struct {
+	studytopic.StudyTopicRepository
+	topics []*studytopic.StudyTopic
+	edges  []studytopic.TopicPrerequisite
+}
+
+func (r *fakeTopicRepo) ListBySubject(string) ([]*studytopic.StudyTopic, error) {
+	return r.topics, nil
+}
+
+func (r *fakeTopicRepo) ListPrerequisitesByUser(uuid.UUID) ([]studytopic.TopicPrerequisite, error) {
+	return r.edges, nil
+}
+
+type fakeSubjectRepo struct {
+	studysubject.StudySubjectRepository
+	subject *studysubject.StudySubject
+}
+
+func (r *fakeSubjectRepo) GetByID(id string) (*studysubject.StudySubject, error) {
+	if r.subject.ID.String() != id {
+		return nil, nil
+	}
+	return r.subject, nil
+}
+
+func TestStudyPlanCreateAndReplanOrderTopicsByPrerequisites(t *testing.T) {
+	config.Init()
+
+	userID := uuid.New()
+	subject := &studysubject.StudySubject{ID: uuid.New(), UserID: userID, Name: "Cálculo"}
+	basics := &studytopic.StudyTopic{ID: uuid.New(), UserID: userID, StudySubjectID: subject.ID, Name: "Limites", Position: 2}
+	advanced := &studytopic.StudyTopic{ID: uuid.New(), UserID: userID, StudySubjectID: subject.ID, Name: "Derivadas", Position: 1}
+
+	repo := &fakePlanRepo{plans: map[uuid.UUID]*StudyPlan{}, tasks: map[uuid.UUID][]*Task{}}
+	topics := &fakeTopicRepo{
+		// a ordem da matéria coloca o tópico dependente primeiro
+		topics: []*studytopic.StudyTopic{advanced, basics},
+		edges:  []studytopic.TopicPrerequisite{{TopicID: advanced.ID, PrerequisiteID: basics.ID, UserID: userID}},
+	}
+	s := &taskService{repo: repo, studyTopicRepo: topics, studySubjectRepo: &fakeSubjectRepo{subject: subject}}
+
+	ctx := context.WithValue(context.Background(), auth.UserDataKeyID, userID.String())
+	ctx = context.WithValue(ctx, auth.UserDataKeyRole, auth.RoleUser)
+
+	exam := &util.LocalDateTime{Time: time.Now().AddDate(0, 0, 21)}
+	plan, err := s.CreateStudyPlan(ctx, subject.ID.String(), &StudyPlanRequest{ExamDate: exam}, false)
+	if err != nil {
+		t.Fatalf("CreateStudyPlan: %v", err)
+	}
+	if first := firstStudyTopic(plan.Tasks); first != basics.ID {
+		t.Fatalf("first study session should cover the prerequisite %s, got %s", basics.ID, first)
+	}
+
+	replanned, err := s.ReplanStudyPlan(ctx, plan.ID.String(), false)
+	if err != nil {
+		t.Fatalf("ReplanStudyPlan: %v", err)
+	}
+	if first := firstStudyTopic(replanned.Tasks); first != basics.ID {
+		t.Fatalf("replanned first study session should cover the prerequisite %s, got %s", basics.ID, first)
+	}
+}
+
+// firstStudyTopic devolve o tópico da sessão de estudo mais cedo do plano.
+func firstStudyTopic(tasks []*Task) uuid.UUID {
+	var first *Task
+	for _, t := range tasks {
+		if t.StudyTopicId == nil || t.StartDate == nil {
+			continue
+		}
+		if first == nil || t.StartDate.Before(first.StartDate.Time) {
+			first = t
+		}
+	}
+	if first == nil {
+		return uuid.Nil
+	}
+	return *first.StudyTopicId
+}
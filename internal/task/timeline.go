@@ -0,0 +1,285 @@
+package task
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/milestone"
+	"github.com/saulo-duarte/chronos-lambda/internal/util"
+)
+
+var (
+	ErrDependencyCycle   = errors.New("dependency would create a cycle")
+	ErrInvalidDependency = errors.New("dependencies must link two different tasks of the same project")
+)
+
+// TaskDependency indica que TaskID só pode começar depois que DependsOnID terminar.
+type TaskDependency struct {
+	TaskID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"taskId"`
+	DependsOnID uuid.UUID `gorm:"type:uuid;primaryKey;column:depends_on_id" json:"dependsOnId"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type TimelineTask struct {
+	ID              uuid.UUID           `json:"id"`
+	Name            string              `json:"name"`
+	Status          TaskStatus          `json:"status"`
+	MilestoneId     *uuid.UUID          `json:"milestoneId"`
+	AssigneeId      *uuid.UUID          `json:"assigneeId"`
+	Start           *util.LocalDateTime `json:"start"`
+	End             *util.LocalDateTime `json:"end"`
+	DueDate         *util.LocalDateTime `json:"dueDate"`
+	DurationMinutes int64               `json:"durationMinutes"`
+	EarliestStart   *util.LocalDateTime `json:"earliestStart"`
+	EarliestFinish  *util.LocalDateTime `json:"earliestFinish"`
+	LatestStart     *util.LocalDateTime `json:"latestStart"`
+	LatestFinish    *util.LocalDateTime `json:"latestFinish"`
+	SlackMinutes    int64               `json:"slackMinutes"`
+	Critical        bool                `json:"critical"`
+	Late            bool                `json:"late"`
+}
+
+type TimelineMilestone struct {
+	ID         uuid.UUID                 `json:"id"`
+	Title      string                    `json:"title"`
+	Status     milestone.MilestoneStatus `json:"status"`
+	TargetDate *util.LocalDateTime       `json:"targetDate"`
+}
+
+type TimelineEdge struct {
+	From uuid.UUID `json:"from"`
+	To   uuid.UUID `json:"to"`
+}
+
+type ProjectTimeline struct {
+	ProjectID    uuid.UUID           `json:"projectId"`
+	Start        *util.LocalDateTime `json:"start"`
+	End          *util.LocalDateTime `json:"end"`
+	Tasks        []TimelineTask      `json:"tasks"`
+	Milestones   []TimelineMilestone `json:"milestones"`
+	Dependencies []TimelineEdge      `json:"dependencies"`
+	CriticalPath []uuid.UUID         `json:"criticalPath"`
+}
+
+func ldt(t time.Time) *util.LocalDateTime {
+	return &util.LocalDateTime{Time: t}
+}
+
+// taskDuration usa o intervalo planejado da task e, na falta dele, a estimativa.
+func taskDuration(t *Task) time.Duration {
+	if t.StartDate != nil && t.DueDate != nil && t.DueDate.After(t.StartDate.Time) {
+		return t.DueDate.Sub(t.StartDate.Time)
+	}
+	return taskEstimate(t, defaultEstimatedMinutes)
+}
+
+// topologicalOrder ordena as tasks de modo que dependências venham antes (Kahn).
+func topologicalOrder(tasks []*Task, deps []TaskDependency) ([]*Task, error) {
+	byID := make(map[uuid.UUID]*Task, len(tasks))
+	indegree := make(map[uuid.UUID]int, len(tasks))
+	successors := make(map[uuid.UUID][]uuid.UUID)
+	for _, t := range tasks {
+		byID[t.ID] = t
+		indegree[t.ID] = 0
+	}
+	for _, d := range deps {
+		if byID[d.TaskID] == nil || byID[d.DependsOnID] == nil {
+			continue
+		}
+		successors[d.DependsOnID] = append(successors[d.DependsOnID], d.TaskID)
+		indegree[d.TaskID]++
+	}
+
+	var queue []uuid.UUID
+	for _, t := range tasks {
+		if indegree[t.ID] == 0 {
+			queue = append(queue, t.ID)
+		}
+	}
+
+	ordered := make([]*Task, 0, len(tasks))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, byID[id])
+		for _, next := range successors[id] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, ErrDependencyCycle
+	}
+	return ordered, nil
+}
+
+// buildTimeline aplica o método do caminho crítico (CPM) com passes de ida e volta.
+func buildTimeline(projectID uuid.UUID, tasks []*Task, deps []TaskDependency, milestones []*milestone.Milestone, now time.Time) (*ProjectTimeline, error) {
+	timeline := &ProjectTimeline{
+		ProjectID:    projectID,
+		Tasks:        []TimelineTask{},
+		Milestones:   []TimelineMilestone{},
+		Dependencies: []TimelineEdge{},
+		CriticalPath: []uuid.UUID{},
+	}
+
+	for _, m := range milestones {
+		timeline.Milestones = append(timeline.Milestones, TimelineMilestone{
+			ID:         m.ID,
+			Title:      m.Title,
+			Status:     m.Status,
+			TargetDate: m.TargetDate,
+		})
+	}
+
+	ordered, err := topologicalOrder(tasks, deps)
+	if err != nil {
+		return nil, err
+	}
+	if len(ordered) == 0 {
+		return timeline, nil
+	}
+
+	predecessors := make(map[uuid.UUID][]uuid.UUID)
+	successors := make(map[uuid.UUID][]uuid.UUID)
+	for _, d := range deps {
+		predecessors[d.TaskID] = append(predecessors[d.TaskID], d.DependsOnID)
+		successors[d.DependsOnID] = append(successors[d.DependsOnID], d.TaskID)
+		timeline.Dependencies = append(timeline.Dependencies, TimelineEdge{From: d.DependsOnID, To: d.TaskID})
+	}
+
+	projectStart := now
+	for _, t := range ordered {
+		if t.StartDate != nil && !t.StartDate.IsZero() && t.StartDate.Before(projectStart) {
+			projectStart = t.StartDate.Time
+		}
+	}
+
+	duration := make(map[uuid.UUID]time.Duration, len(ordered))
+	es := make(map[uuid.UUID]time.Time, len(ordered))
+	ef := make(map[uuid.UUID]time.Time, len(ordered))
+	projectEnd := projectStart
+
+	for _, t := range ordered {
+		duration[t.ID] = taskDuration(t)
+
+		start := projectStart
+		if t.StartDate != nil && !t.StartDate.IsZero() {
+			start = t.StartDate.Time
+		}
+		for _, p := range predecessors[t.ID] {
+			if finish, ok := ef[p]; ok && finish.After(start) {
+				start = finish
+			}
+		}
+		es[t.ID] = start
+		ef[t.ID] = start.Add(duration[t.ID])
+		if ef[t.ID].After(projectEnd) {
+			projectEnd = ef[t.ID]
+		}
+	}
+
+	ls := make(map[uuid.UUID]time.Time, len(ordered))
+	lf := make(map[uuid.UUID]time.Time, len(ordered))
+	for i := len(ordered) - 1; i >= 0; i-- {
+		t := ordered[i]
+		finish := projectEnd
+		for _, succ := range successors[t.ID] {
+			if start, ok := ls[succ]; ok && start.Before(finish) {
+				finish = start
+			}
+		}
+		lf[t.ID] = finish
+		ls[t.ID] = finish.Add(-duration[t.ID])
+	}
+
+	for _, t := range ordered {
+		slack := ls[t.ID].Sub(es[t.ID])
+		critical := slack <= 0
+		timeline.Tasks = append(timeline.Tasks, TimelineTask{
+			ID:              t.ID,
+			Name:            t.Name,
+			Status:          t.Status,
+			MilestoneId:     t.MilestoneId,
+			AssigneeId:      t.AssigneeId,
+			Start:           ldt(es[t.ID]),
+			End:             ldt(ef[t.ID]),
+			DueDate:         t.DueDate,
+			DurationMinutes: int64(duration[t.ID] / time.Minute),
+			EarliestStart:   ldt(es[t.ID]),
+			EarliestFinish:  ldt(ef[t.ID]),
+			LatestStart:     ldt(ls[t.ID]),
+			LatestFinish:    ldt(lf[t.ID]),
+			SlackMinutes:    int64(slack / time.Minute),
+			Critical:        critical,
+			Late:            t.DueDate != nil && !t.DueDate.IsZero() && ef[t.ID].After(t.DueDate.Time),
+		})
+	}
+
+	timeline.Start = ldt(projectStart)
+	timeline.End = ldt(projectEnd)
+	timeline.CriticalPath = criticalPath(ordered, successors, es, ef, projectStart, projectEnd, ls)
+
+	return timeline, nil
+}
+
+// criticalPath percorre a cadeia de tasks sem folga do início ao fim do projeto.
+func criticalPath(ordered []*Task, successors map[uuid.UUID][]uuid.UUID, es, ef map[uuid.UUID]time.Time, projectStart, projectEnd time.Time, ls map[uuid.UUID]time.Time) []uuid.UUID {
+	isCritical := func(id uuid.UUID) bool { return !ls[id].After(es[id]) }
+
+	var current *uuid.UUID
+	for _, t := range ordered {
+		if isCritical(t.ID) && es[t.ID].Equal(projectStart) {
+			id := t.ID
+			current = &id
+			break
+		}
+	}
+
+	path := []uuid.UUID{}
+	for current != nil {
+		path = append(path, *current)
+		if ef[*current].Equal(projectEnd) {
+			break
+		}
+		var next *uuid.UUID
+		for _, succ := range successors[*current] {
+			if isCritical(succ) && es[succ].Equal(ef[*current]) {
+				id := succ
+				next = &id
+				break
+			}
+		}
+		current = next
+	}
+	return path
+}
+
+// createsCycle verifica se adicionar taskID -> dependsOnID fecharia um ciclo,
+// ou seja, se taskID já é alcançável a partir de dependsOnID seguindo as dependências.
+func createsCycle(deps []TaskDependency, taskID, dependsOnID uuid.UUID) bool {
+	requires := make(map[uuid.UUID][]uuid.UUID)
+	for _, d := range deps {
+		requires[d.TaskID] = append(requires[d.TaskID], d.DependsOnID)
+	}
+
+	visited := map[uuid.UUID]bool{}
+	stack := []uuid.UUID{dependsOnID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == taskID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, requires[id]...)
+	}
+	return false
+}
@@ -1,6 +1,10 @@
 package user
 
-import "gorm.io/gorm"
+import (
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+	"gorm.io/gorm"
+)
 
 type UserContainer struct {
 	Handler *Handler
@@ -9,8 +13,16 @@ type UserContainer struct {
 
 func NewUserContainer(db *gorm.DB) *UserContainer {
 	repo := NewRepository(db)
-	service := NewService(repo)
-	handler := NewHandler(service)
+	verifier, err := auth.NewGoogleIDTokenVerifierFromEnv()
+	if err != nil {
+		config.Logger.WithError(err).Warn("Login por ID token do Google desativado")
+	}
+	service := NewService(repo, verifier)
+	google, err := auth.NewGoogleOAuthFromEnv()
+	if err != nil {
+		config.Logger.WithError(err).Warn("Login OAuth do Google desativado")
+	}
+	handler := NewHandler(service, google)
 
 	return &UserContainer{
 		Handler: handler,
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 )
 
 type User struct {
@@ -47,14 +48,10 @@ func (u *User) HasRole(role string) bool {
 }
 
 func (u *User) IsAdmin() bool {
-	return u.HasRole("ADMIN")
+	return u.HasRole(auth.RoleAdmin)
 }
 
-func (u *User) CanAccess(resource string) bool {
-	switch resource {
-	case "admin":
-		return u.IsAdmin()
-	default:
-		return false
-	}
+// CanAccess consulta a matriz de permissões do papel do usuário.
+func (u *User) CanAccess(permission auth.Permission) bool {
+	return auth.HasPermission(u.Role, permission)
 }
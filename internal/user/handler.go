@@ -2,10 +2,14 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 	"github.com/saulo-duarte/chronos-lambda/internal/config"
 )
@@ -16,10 +20,13 @@ var API_DOMAIN = os.Getenv("API_DOMAIN")
 
 type Handler struct {
 	service UserService
+	google  *auth.GoogleOAuth
 }
 
-func NewHandler(s UserService) *Handler {
-	return &Handler{service: s}
+// NewHandler recebe google nulo quando o OAuth não está configurado; nesse caso o fluxo
+// /auth/google responde 503.
+func NewHandler(s UserService, google *auth.GoogleOAuth) *Handler {
+	return &Handler{service: s, google: google}
 }
 
 func newCookie(name, value string, maxAge int) *http.Cookie {
@@ -37,36 +44,60 @@ func newCookie(name, value string, maxAge int) *http.Cookie {
 	return c
 }
 
+func setSessionCookies(w http.ResponseWriter, jwtToken, refreshToken string) {
+	http.SetCookie(w, newCookie(auth.JWT_COOKIE_NAME, jwtToken, int(auth.AccessTokenTTL().Seconds())))
+	http.SetCookie(w, newCookie(auth.REFRESH_TOKEN_COOKIE_NAME, refreshToken, int(auth.RefreshTokenTTL().Seconds())))
+}
+
+func clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, newCookie(auth.JWT_COOKIE_NAME, "", -1))
+	http.SetCookie(w, newCookie(auth.REFRESH_TOKEN_COOKIE_NAME, "", -1))
+}
+
+// clientInfo depende do middleware.RealIP para que RemoteAddr já seja o IP do cliente.
+func clientInfo(r *http.Request) ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ClientInfo{UserAgent: r.UserAgent(), IP: ip}
+}
+
 func (h *Handler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 	log := config.WithContext(r.Context())
 
 	// LOG DE DEBUG ADICIONADO AQUI
 	log.WithField("API_DOMAIN_CONFIG", API_DOMAIN).Info("Verificando a variável API_DOMAIN antes de setar o cookie")
 
-	var payload auth.AuthResult
+	var payload GoogleLoginDTO
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		log.WithError(err).Error("Corpo da requisição inválido")
 		http.Error(w, "invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if payload.ProviderID == "" || payload.Email == "" {
-		http.Error(w, "providerID and email are required", http.StatusBadRequest)
+	if payload.IDToken == "" {
+		http.Error(w, "id_token is required", http.StatusBadRequest)
 		return
 	}
 
-	user, jwtToken, refreshToken, err := h.service.LoginWithGoogleUser(r.Context(), &payload)
+	user, jwtToken, refreshToken, err := h.service.LoginWithGoogleIDToken(r.Context(), &payload, clientInfo(r))
 	if err != nil {
-		log.WithError(err).Error("Falha no login via Google")
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, auth.ErrIDTokenNotConfigured):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		case errors.Is(err, auth.ErrInvalidIDToken):
+			http.Error(w, "invalid id token", http.StatusUnauthorized)
+		case errors.Is(err, auth.ErrEmailNotVerified):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			log.WithError(err).Error("Falha no login via Google")
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	jwtCookie := newCookie(auth.JWT_COOKIE_NAME, jwtToken, int((24 * time.Hour).Seconds()))
-	refreshCookie := newCookie(auth.REFRESH_TOKEN_COOKIE_NAME, refreshToken, int((14 * 24 * time.Hour).Seconds()))
-
-	http.SetCookie(w, jwtCookie)
-	http.SetCookie(w, refreshCookie)
+	setSessionCookies(w, jwtToken, refreshToken)
 
 	config.JSON(w, http.StatusOK, map[string]any{
 		"user":    user.ToResponse(),
@@ -85,18 +116,164 @@ func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newJWT, err := h.service.RefreshToken(r.Context(), cookie.Value)
+	newJWT, newRefresh, err := h.service.RefreshToken(r.Context(), cookie.Value, clientInfo(r))
 	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrRefreshTokenReused) || errors.Is(err, ErrUserNotFound) {
+			log.WithError(err).Warn("Refresh token recusado")
+			clearSessionCookies(w)
+			config.JSON(w, http.StatusUnauthorized, map[string]string{
+				"error": "failed to refresh token",
+			})
+			return
+		}
 		log.WithError(err).Error("Falha ao atualizar o token")
-		config.JSON(w, http.StatusUnauthorized, map[string]string{
-			"error": "failed to refresh token",
-		})
+		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
 
-	http.SetCookie(w, newCookie(auth.JWT_COOKIE_NAME, newJWT, int((24*time.Hour).Seconds())))
+	setSessionCookies(w, newJWT, newRefresh)
 
 	config.JSON(w, http.StatusOK, map[string]string{
 		"message": "token refreshed successfully",
 	})
 }
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	refreshToken := ""
+	if c, err := r.Cookie(auth.REFRESH_TOKEN_COOKIE_NAME); err == nil {
+		refreshToken = c.Value
+	}
+	accessToken, _ := auth.ExtractToken(r)
+
+	// os cookies saem mesmo se a revogação falhar
+	clearSessionCookies(w)
+	if err := h.service.Logout(r.Context(), refreshToken, accessToken); err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "logged out successfully",
+	})
+}
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.service.ListSessions(r.Context())
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	config.JSON(w, http.StatusOK, map[string]any{
+		"count":    len(sessions),
+		"sessions": sessions,
+	})
+}
+
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	if err := h.service.RevokeSession(r.Context(), sessionID); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	if claims, err := auth.GetUserClaimsFromContext(r.Context()); err == nil && claims.SessionID == sessionID {
+		clearSessionCookies(w)
+	}
+	config.JSON(w, http.StatusOK, map[string]string{
+		"message": "session revoked successfully",
+	})
+}
+
+func writeSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrNoAuthData):
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, ErrSessionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+// oauthCookie guarda o verificador PKCE. Precisa de SameSite=Lax para voltar no redirecionamento
+// vindo do Google e fica restrito aos caminhos do fluxo.
+func oauthCookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     auth.OAUTH_VERIFIER_COOKIE_NAME,
+		Value:    value,
+		Path:     "/auth/google",
+		HttpOnly: true,
+		MaxAge:   maxAge,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   ENV == "prod",
+		Domain:   API_DOMAIN,
+	}
+}
+
+func (h *Handler) GoogleStart(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if h.google == nil {
+		http.Error(w, auth.ErrOAuthNotConfigured.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	start, err := h.google.Start(r.URL.Query().Get("return_to"))
+	if err != nil {
+		log.WithError(err).Error("Falha ao iniciar o fluxo OAuth do Google")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, oauthCookie(start.Verifier, int((10*time.Minute).Seconds())))
+	http.Redirect(w, r, start.URL, http.StatusFound)
+}
+
+func (h *Handler) GoogleCallback(w http.ResponseWriter, r *http.Request) {
+	log := config.WithContext(r.Context())
+
+	if h.google == nil {
+		http.Error(w, auth.ErrOAuthNotConfigured.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		log.WithField("oauth_error", e).Warn("Google recusou a autorização")
+		http.Error(w, "google authorization denied", http.StatusUnauthorized)
+		return
+	}
+
+	verifier := ""
+	if c, err := r.Cookie(auth.OAUTH_VERIFIER_COOKIE_NAME); err == nil {
+		verifier = c.Value
+	}
+	// o verificador é de uso único, então o cookie sai mesmo se a troca falhar
+	http.SetCookie(w, oauthCookie("", -1))
+
+	result, returnTo, err := h.google.Exchange(r.Context(), query.Get("code"), query.Get("state"), verifier)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidOAuthState):
+			log.WithError(err).Warn("State OAuth inválido no callback do Google")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, auth.ErrEmailNotVerified):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			log.WithError(err).Error("Falha na troca do código OAuth do Google")
+			http.Error(w, "failed to authenticate with google", http.StatusBadGateway)
+		}
+		return
+	}
+
+	_, jwtToken, refreshToken, err := h.service.LoginWithGoogleUser(r.Context(), result, clientInfo(r))
+	if err != nil {
+		log.WithError(err).Error("Falha no login via Google")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	setSessionCookies(w, jwtToken, refreshToken)
+	http.Redirect(w, r, strings.TrimRight(FRONTEND_URL, "/")+returnTo, http.StatusFound)
+}
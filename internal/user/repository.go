@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,6 +14,13 @@ type UserRepository interface {
 	GetUserEncryptedGoogleCalendarAccessToken(id string) (string, error)
 	Update(u *User) error
 	Delete(id string) error
+
+	CreateSession(s *Session) error
+	GetSession(id string) (*Session, error)
+	ListActiveSessions(userID string, now time.Time) ([]Session, error)
+	TouchSession(id string, at time.Time) error
+	RotateSession(id, oldHash, newHash string, expiresAt time.Time, client ClientInfo) (bool, error)
+	RevokeSession(id, reason string) error
 }
 
 type userRepository struct {
@@ -67,3 +75,55 @@ func (r *userRepository) Update(u *User) error {
 func (r *userRepository) Delete(id string) error {
 	return r.db.Delete(&User{}, "id = ?", id).Error
 }
+
+func (r *userRepository) CreateSession(s *Session) error {
+	return r.db.Create(s).Error
+}
+
+func (r *userRepository) GetSession(id string) (*Session, error) {
+	var s Session
+	if err := r.db.First(&s, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *userRepository) ListActiveSessions(userID string, now time.Time) ([]Session, error) {
+	var sessions []Session
+	err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *userRepository) TouchSession(id string, at time.Time) error {
+	return r.db.Model(&Session{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+// RotateSession só troca o hash se ele ainda for o esperado. Duas renovações concorrentes com o
+// mesmo token não passam as duas: a segunda recebe false.
+func (r *userRepository) RotateSession(id, oldHash, newHash string, expiresAt time.Time, client ClientInfo) (bool, error) {
+	res := r.db.Model(&Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]any{
+			"refresh_token_hash": newHash,
+			"expires_at":         expiresAt,
+			"last_seen_at":       time.Now(),
+			"user_agent":         client.UserAgent,
+			"ip":                 client.IP,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *userRepository) RevokeSession(id, reason string) error {
+	return r.db.Model(&Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
 )
 
 func Routes(h *Handler) chi.Router {
@@ -9,6 +10,25 @@ func Routes(h *Handler) chi.Router {
 
 	r.Post("/login", h.GoogleLogin)
 	r.Post("/refresh", h.RefreshToken)
+	r.Post("/logout", h.Logout)
+
+	r.Group(func(r chi.Router) {
+		r.Use(auth.AuthMiddleware)
+		r.Use(auth.RequirePermission(auth.PermissionManageSessions))
+
+		r.Get("/me/sessions", h.ListSessions)
+		r.Delete("/me/sessions/{id}", h.RevokeSession)
+	})
+
+	return r
+}
+
+// AuthRoutes é o fluxo OAuth do Google feito pelo servidor, montado em /auth.
+func AuthRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/google/start", h.GoogleStart)
+	r.Get("/google/callback", h.GoogleCallback)
 
 	return r
 }
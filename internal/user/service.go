@@ -11,24 +11,79 @@ import (
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrSessionNotFound     = errors.New("session not found")
 )
 
+// GoogleLoginDTO é o corpo de /users/login. A identidade vem só do ID token verificado;
+// os tokens de acesso do Google são opcionais e servem para a integração com a agenda.
+type GoogleLoginDTO struct {
+	IDToken      string `json:"id_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 type UserService interface {
-	LoginWithGoogleUser(ctx context.Context, authResult *auth.AuthResult) (*User, string, string, error)
-	Login(ctx context.Context, providerID string) (*User, string, string, error)
-	RefreshToken(ctx context.Context, tokenString string) (string, error)
+	LoginWithGoogleUser(ctx context.Context, authResult *auth.AuthResult, client ClientInfo) (*User, string, string, error)
+	LoginWithGoogleIDToken(ctx context.Context, dto *GoogleLoginDTO, client ClientInfo) (*User, string, string, error)
+	RefreshToken(ctx context.Context, tokenString string, client ClientInfo) (string, string, error)
+	Logout(ctx context.Context, refreshToken, accessToken string) error
+	ListSessions(ctx context.Context) ([]*SessionResponse, error)
+	RevokeSession(ctx context.Context, sessionID string) error
 }
 
 type userService struct {
-	repo UserRepository
+	repo     UserRepository
+	verifier *auth.GoogleIDTokenVerifier
+}
+
+// NewService recebe verifier nulo quando GOOGLE_CLIENT_ID não está configurado; nesse caso
+// o login por ID token é recusado.
+func NewService(repo UserRepository, verifier *auth.GoogleIDTokenVerifier) UserService {
+	return &userService{repo: repo, verifier: verifier}
 }
 
-func NewService(repo UserRepository) UserService {
-	return &userService{repo: repo}
+func (s *userService) LoginWithGoogleIDToken(ctx context.Context, dto *GoogleLoginDTO, client ClientInfo) (*User, string, string, error) {
+	log := config.WithContext(ctx)
+
+	if s.verifier == nil {
+		return nil, "", "", auth.ErrIDTokenNotConfigured
+	}
+	if dto.IDToken == "" {
+		return nil, "", "", auth.ErrInvalidIDToken
+	}
+
+	claims, err := s.verifier.Verify(ctx, dto.IDToken)
+	if err != nil {
+		log.WithError(err).Warn("ID token do Google rejeitado")
+		return nil, "", "", err
+	}
+
+	authResult := &auth.AuthResult{
+		ProviderID: claims.Subject,
+		Username:   claims.Name,
+		Email:      claims.Email,
+		Picture:    claims.Picture,
+	}
+	if dto.AccessToken != "" {
+		if authResult.AccessToken, err = config.Encrypt(dto.AccessToken); err != nil {
+			log.WithError(err).Error("Falha ao criptografar access token do Google")
+			return nil, "", "", err
+		}
+	}
+	if dto.RefreshToken != "" {
+		if authResult.RefreshToken, err = config.Encrypt(dto.RefreshToken); err != nil {
+			log.WithError(err).Error("Falha ao criptografar refresh token do Google")
+			return nil, "", "", err
+		}
+	}
+
+	return s.LoginWithGoogleUser(ctx, authResult, client)
 }
 
-func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.AuthResult) (*User, string, string, error) {
+func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.AuthResult, client ClientInfo) (*User, string, string, error) {
 	log := config.WithContext(ctx)
 
 	providerID := authResult.ProviderID
@@ -48,7 +103,7 @@ func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.
 			Username:                    authResult.Username,
 			Email:                       authResult.Email,
 			AvatarURL:                   authResult.Picture,
-			Role:                        "USER",
+			Role:                        auth.RoleUser,
 			EncryptedGoogleAccessToken:  authResult.AccessToken,
 			EncryptedGoogleRefreshToken: authResult.RefreshToken,
 			CreatedAt:                   time.Now(),
@@ -64,8 +119,14 @@ func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.
 		user.Username = authResult.Username
 		user.Email = authResult.Email
 		user.AvatarURL = authResult.Picture
-		user.EncryptedGoogleAccessToken = authResult.AccessToken
-		user.EncryptedGoogleRefreshToken = authResult.RefreshToken
+		// login só com ID token não traz access token do Google; sem ele, mantém o da agenda
+		if authResult.AccessToken != "" {
+			user.EncryptedGoogleAccessToken = authResult.AccessToken
+		}
+		// o Google só manda refresh token no primeiro consentimento; sem ele, mantém o atual
+		if authResult.RefreshToken != "" {
+			user.EncryptedGoogleRefreshToken = authResult.RefreshToken
+		}
 		user.UpdatedAt = time.Now()
 		if err := s.repo.Update(user); err != nil {
 			log.WithError(err).Error("Falha ao atualizar usuário existente")
@@ -74,15 +135,9 @@ func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.
 		log.WithField("user_id", user.ID).Info("Usuário atualizado com sucesso")
 	}
 
-	jwtToken, err := auth.GenerateJWT(user.ID.String(), user.Role, 24*time.Hour)
-	if err != nil {
-		log.WithError(err).Error("Falha ao gerar JWT")
-		return nil, "", "", err
-	}
-
-	refreshToken, err := auth.GenerateJWT(user.ID.String(), user.Role, 14*24*time.Hour)
+	jwtToken, refreshToken, err := s.startSession(user, client)
 	if err != nil {
-		log.WithError(err).Error("Falha ao gerar refresh token")
+		log.WithError(err).Error("Falha ao abrir sessão")
 		return nil, "", "", err
 	}
 
@@ -91,60 +146,179 @@ func (s *userService) LoginWithGoogleUser(ctx context.Context, authResult *auth.
 	return user, jwtToken, refreshToken, nil
 }
 
-func (s *userService) Login(ctx context.Context, providerID string) (*User, string, string, error) {
-	log := config.WithContext(ctx)
-
-	user, err := s.repo.GetByProviderID(providerID)
-	if err != nil {
-		log.WithError(err).Error("Erro ao buscar usuário por provider ID")
-		return nil, "", "", ErrUserNotFound
-	}
-	if user == nil {
-		log.WithField("provider_id", providerID).Warn("Usuário não encontrado para login")
-		return nil, "", "", ErrUserNotFound
+func (s *userService) startSession(user *User, client ClientInfo) (string, string, error) {
+	now := time.Now()
+	session := &Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
 	}
+	session.ExpiresAt = session.refreshExpiry(now, auth.RefreshTokenTTL(), auth.SessionMaxAge())
 
-	jwtToken, err := auth.GenerateJWT(user.ID.String(), user.Role, 24*time.Hour)
+	accessToken, err := auth.GenerateAccessToken(user.ID.String(), user.Role, session.ID.String())
 	if err != nil {
-		log.WithError(err).Error("Falha ao gerar JWT")
-		return nil, "", "", err
+		return "", "", err
 	}
-
-	refreshToken, err := auth.GenerateJWT(user.ID.String(), user.Role, 14*24*time.Hour)
+	refreshToken, err := auth.GenerateRefreshToken(user.ID.String(), user.Role, session.ID.String(), session.ExpiresAt)
 	if err != nil {
-		log.WithError(err).Error("Falha ao gerar refresh token")
-		return nil, "", "", err
+		return "", "", err
 	}
+	session.RefreshTokenHash = auth.HashToken(refreshToken)
 
-	log.WithField("user_id", user.ID).Info("Login de usuário realizado com sucesso")
-	return user, jwtToken, refreshToken, nil
+	if err := s.repo.CreateSession(session); err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
 }
 
-func (s *userService) RefreshToken(ctx context.Context, tokenString string) (string, error) {
+// RefreshToken gira o refresh token da sessão. Um token assinado por nós mas que não é o
+// último emitido já foi usado antes, então a sessão inteira é revogada.
+func (s *userService) RefreshToken(ctx context.Context, tokenString string, client ClientInfo) (string, string, error) {
 	log := config.WithContext(ctx)
 
-	claims, err := auth.ValidateJWT(tokenString)
+	claims, err := auth.ValidateRefreshToken(tokenString)
 	if err != nil {
 		log.WithError(err).Warn("Refresh token inválido")
-		return "", errors.New("invalid refresh token")
+		return "", "", ErrInvalidRefreshToken
+	}
+	log = log.WithField("session_id", claims.SessionID)
+
+	session, err := s.repo.GetSession(claims.SessionID)
+	if err != nil {
+		log.WithError(err).Error("Erro ao buscar sessão para refresh token")
+		return "", "", err
+	}
+	now := time.Now()
+	if session == nil || !auth.IsOwner(claims.UserID, session.UserID) || !session.IsActive(now) {
+		log.Warn("Sessão inexistente, expirada ou revogada")
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	oldHash := auth.HashToken(tokenString)
+	if session.RefreshTokenHash != oldHash {
+		s.revokeReusedSession(ctx, session.ID.String())
+		return "", "", ErrRefreshTokenReused
 	}
 
 	user, err := s.repo.GetByID(claims.UserID)
 	if err != nil {
 		log.WithError(err).Error("Erro ao buscar usuário para refresh token")
-		return "", err
+		return "", "", err
 	}
 	if user == nil {
 		log.WithField("user_id", claims.UserID).Warn("Usuário não encontrado para refresh token")
-		return "", ErrUserNotFound
+		return "", "", ErrUserNotFound
 	}
 
-	newJWT, err := auth.GenerateJWT(user.ID.String(), user.Role, 24*time.Hour)
+	expiresAt := session.refreshExpiry(now, auth.RefreshTokenTTL(), auth.SessionMaxAge())
+	newRefresh, err := auth.GenerateRefreshToken(user.ID.String(), user.Role, session.ID.String(), expiresAt)
+	if err != nil {
+		log.WithError(err).Error("Falha ao gerar novo refresh token")
+		return "", "", err
+	}
+	newJWT, err := auth.GenerateAccessToken(user.ID.String(), user.Role, session.ID.String())
 	if err != nil {
 		log.WithError(err).Error("Falha ao gerar novo JWT")
-		return "", err
+		return "", "", err
+	}
+
+	rotated, err := s.repo.RotateSession(session.ID.String(), oldHash, auth.HashToken(newRefresh), expiresAt, client)
+	if err != nil {
+		log.WithError(err).Error("Falha ao girar refresh token")
+		return "", "", err
+	}
+	if !rotated {
+		// outra requisição girou o mesmo token antes desta
+		s.revokeReusedSession(ctx, session.ID.String())
+		return "", "", ErrRefreshTokenReused
 	}
 
 	log.WithField("user_id", user.ID).Info("JWT atualizado com sucesso")
-	return newJWT, nil
+	return newJWT, newRefresh, nil
+}
+
+func (s *userService) revokeReusedSession(ctx context.Context, sessionID string) {
+	log := config.WithContext(ctx).WithField("session_id", sessionID)
+	log.Warn("Reuso de refresh token detectado, revogando a sessão")
+	if err := s.repo.RevokeSession(sessionID, "refresh token reuse"); err != nil {
+		log.WithError(err).Error("Falha ao revogar sessão")
+	}
+}
+
+// Logout encerra a sessão indicada pelo refresh token ou, na falta dele, pelo access token.
+// Tokens inválidos não são erro: o logout só não tem o que revogar.
+func (s *userService) Logout(ctx context.Context, refreshToken, accessToken string) error {
+	log := config.WithContext(ctx)
+
+	var claims *auth.Claims
+	if refreshToken != "" {
+		claims, _ = auth.ValidateRefreshToken(refreshToken)
+	}
+	if claims == nil && accessToken != "" {
+		claims, _ = auth.ValidateAccessToken(accessToken)
+	}
+	if claims == nil {
+		log.Info("Logout sem sessão válida para revogar")
+		return nil
+	}
+
+	if err := s.revokeOwnSession(claims.UserID, claims.SessionID, "logout"); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		log.WithError(err).Error("Falha ao revogar sessão no logout")
+		return err
+	}
+	log.WithField("session_id", claims.SessionID).Info("Logout concluído")
+	return nil
+}
+
+func (s *userService) ListSessions(ctx context.Context) ([]*SessionResponse, error) {
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.repo.ListActiveSessions(claims.UserID, time.Now())
+	if err != nil {
+		config.WithContext(ctx).WithError(err).Error("Erro ao listar sessões")
+		return nil, err
+	}
+	result := make([]*SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		result = append(result, sessions[i].ToResponse(claims.SessionID))
+	}
+	return result, nil
+}
+
+func (s *userService) RevokeSession(ctx context.Context, sessionID string) error {
+	log := config.WithContext(ctx)
+
+	claims, err := auth.GetUserClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.revokeOwnSession(claims.UserID, sessionID, "revoked by user"); err != nil {
+		if !errors.Is(err, ErrSessionNotFound) {
+			log.WithError(err).Error("Falha ao revogar sessão")
+		}
+		return err
+	}
+	log.WithField("session_id", sessionID).Info("Sessão revogada pelo usuário")
+	return nil
+}
+
+// revokeOwnSession trata sessão de outro usuário como inexistente.
+func (s *userService) revokeOwnSession(userID, sessionID, reason string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return ErrSessionNotFound
+	}
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || !auth.IsOwner(userID, session.UserID) || !session.IsActive(time.Now()) {
+		return ErrSessionNotFound
+	}
+	return s.repo.RevokeSession(sessionID, reason)
 }
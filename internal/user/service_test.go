@@ -0,0 +1,297 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+	"github.com/saulo-duarte/chronos-lambda/internal/config"
+)
+
+// fakeRepo guarda usuários e sessões em memória. RotateSession faz o mesmo compare-and-swap
+// do repositório real, e os métodos que login e refresh não usam caem na interface nula.
+type fakeRepo struct {
+	UserRepository
+
+	mu       sync.Mutex
+	users    map[string]*User
+	sessions map[string]Session
+	revoked  map[string]string
+}
+
+func newFakeRepo(users ...*User) *fakeRepo {
+	r := &fakeRepo{users: map[string]*User{}, sessions: map[string]Session{}, revoked: map[string]string{}}
+	for _, u := range users {
+		r.users[u.ID.String()] = u
+	}
+	return r
+}
+
+func (r *fakeRepo) GetByID(id string) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[id], nil
+}
+
+func (r *fakeRepo) GetByProviderID(providerID string) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.ProviderID == providerID {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepo) Update(u *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *u
+	r.users[u.ID.String()] = &stored
+	return nil
+}
+
+func (r *fakeRepo) CreateSession(s *Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[s.ID.String()] = *s
+	return nil
+}
+
+func (r *fakeRepo) GetSession(id string) (*Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (r *fakeRepo) RotateSession(id, oldHash, newHash string, expiresAt time.Time, client ClientInfo) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || s.RevokedAt != nil || s.RefreshTokenHash != oldHash {
+		return false, nil
+	}
+	s.RefreshTokenHash = newHash
+	s.ExpiresAt = expiresAt
+	s.UserAgent, s.IP = client.UserAgent, client.IP
+	r.sessions[id] = s
+	return true, nil
+}
+
+func (r *fakeRepo) RevokeSession(id, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.sessions[id]
+	now := time.Now()
+	s.RevokedAt = &now
+	r.sessions[id] = s
+	r.revoked[id] = reason
+	return nil
+}
+
+func (r *fakeRepo) revocation(id string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reason, ok := r.revoked[id]
+	return reason, ok
+}
+
+func setupRefresh(t *testing.T) (*userService, *fakeRepo, *User) {
+	t.Helper()
+	config.Init()
+	t.Setenv("JWT_SECRET", "test-secret-with-enough-entropy-123")
+	t.Setenv("JWT_SIGNING_KEY", "")
+	t.Setenv("JWT_SIGNING_KEY_FILE", "")
+	auth.Init()
+
+	user := &User{ID: uuid.New(), Role: auth.RoleUser, Email: "ana@example.com"}
+	repo := newFakeRepo(user)
+	return &userService{repo: repo}, repo, user
+}
+
+func sessionIDOf(t *testing.T, refreshToken string) string {
+	t.Helper()
+	claims, err := auth.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		t.Fatalf("ValidateRefreshToken: %v", err)
+	}
+	return claims.SessionID
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	s, repo, user := setupRefresh(t)
+	_, refresh, err := s.startSession(user, ClientInfo{UserAgent: "phone"})
+	if err != nil {
+		t.Fatalf("startSession: %v", err)
+	}
+
+	access, rotated, err := s.RefreshToken(context.Background(), refresh, ClientInfo{UserAgent: "phone", IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if rotated == refresh {
+		t.Fatal("refresh token was not rotated")
+	}
+	if _, err := auth.ValidateAccessToken(access); err != nil {
+		t.Errorf("new access token is invalid: %v", err)
+	}
+	session, _ := repo.GetSession(sessionIDOf(t, refresh))
+	if session.RefreshTokenHash != auth.HashToken(rotated) || session.IP != "10.0.0.1" {
+		t.Errorf("session not updated by rotation: %+v", session)
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	s, repo, user := setupRefresh(t)
+	ctx := context.Background()
+	_, first, err := s.startSession(user, ClientInfo{})
+	if err != nil {
+		t.Fatalf("startSession: %v", err)
+	}
+	_, second, err := s.RefreshToken(ctx, first, ClientInfo{})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	// o token antigo volta: alguém guardou uma cópia
+	if _, _, err := s.RefreshToken(ctx, first, ClientInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replayed token: err = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, ok := repo.revocation(sessionIDOf(t, first)); !ok {
+		t.Fatal("session was not revoked after reuse")
+	}
+
+	// a revogação derruba a família inteira, inclusive o token mais novo
+	if _, _, err := s.RefreshToken(ctx, second, ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("latest token after revocation: err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshTokenConcurrentRotation(t *testing.T) {
+	s, repo, user := setupRefresh(t)
+	_, refresh, err := s.startSession(user, ClientInfo{})
+	if err != nil {
+		t.Fatalf("startSession: %v", err)
+	}
+
+	const callers = 8
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, errs[i] = s.RefreshToken(context.Background(), refresh, ClientInfo{})
+		}()
+	}
+	wg.Wait()
+
+	// só uma requisição pode girar o token; qualquer outra é tratada como reuso, tenha ela
+	// perdido no compare-and-swap (rotated == false) ou lido a sessão já girada
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrRefreshTokenReused), errors.Is(err, ErrInvalidRefreshToken):
+		default:
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d concurrent rotations succeeded, want 1", succeeded)
+	}
+	if _, ok := repo.revocation(sessionIDOf(t, refresh)); !ok {
+		t.Fatal("session was not revoked after concurrent reuse")
+	}
+}
+
+// lostRaceRepo simula outra requisição girando o token entre a leitura da sessão e o
+// compare-and-swap, o caminho em que RotateSession devolve false.
+type lostRaceRepo struct {
+	*fakeRepo
+}
+
+func (r *lostRaceRepo) RotateSession(id, oldHash, newHash string, expiresAt time.Time, client ClientInfo) (bool, error) {
+	if _, err := r.fakeRepo.RotateSession(id, oldHash, "rotated-by-another-request", expiresAt, client); err != nil {
+		return false, err
+	}
+	return r.fakeRepo.RotateSession(id, oldHash, newHash, expiresAt, client)
+}
+
+func TestRefreshTokenLostRotationRevokesSession(t *testing.T) {
+	s, repo, user := setupRefresh(t)
+	_, refresh, err := s.startSession(user, ClientInfo{})
+	if err != nil {
+		t.Fatalf("startSession: %v", err)
+	}
+	s.repo = &lostRaceRepo{repo}
+
+	if _, _, err := s.RefreshToken(context.Background(), refresh, ClientInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("err = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, ok := repo.revocation(sessionIDOf(t, refresh)); !ok {
+		t.Fatal("session was not revoked after losing the rotation")
+	}
+}
+
+func TestRefreshTokenRejectsAccessToken(t *testing.T) {
+	s, repo, user := setupRefresh(t)
+	access, refresh, err := s.startSession(user, ClientInfo{})
+	if err != nil {
+		t.Fatalf("startSession: %v", err)
+	}
+
+	if _, _, err := s.RefreshToken(context.Background(), access, ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("err = %v, want ErrInvalidRefreshToken", err)
+	}
+	// recusar o token errado não pode revogar a sessão nem consumir o refresh válido
+	if _, ok := repo.revocation(sessionIDOf(t, refresh)); ok {
+		t.Fatal("session revoked by an access token")
+	}
+	if _, _, err := s.RefreshToken(context.Background(), refresh, ClientInfo{}); err != nil {
+		t.Fatalf("refresh after rejected access token: %v", err)
+	}
+}
+
+func TestLoginWithGoogleUserKeepsCalendarTokens(t *testing.T) {
+	s, repo, user := setupRefresh(t)
+	user.ProviderID = "google-123"
+	user.EncryptedGoogleAccessToken = "stored-access"
+	user.EncryptedGoogleRefreshToken = "stored-refresh"
+
+	// login por ID token: só identidade, sem tokens do Google
+	if _, _, _, err := s.LoginWithGoogleUser(context.Background(), &auth.AuthResult{
+		ProviderID: "google-123",
+		Username:   "Ana",
+		Email:      "ana@example.com",
+	}, ClientInfo{}); err != nil {
+		t.Fatalf("LoginWithGoogleUser: %v", err)
+	}
+	stored, _ := repo.GetByID(user.ID.String())
+	if stored.EncryptedGoogleAccessToken != "stored-access" || stored.EncryptedGoogleRefreshToken != "stored-refresh" {
+		t.Fatalf("login without Google tokens overwrote them: %+v", stored)
+	}
+
+	if _, _, _, err := s.LoginWithGoogleUser(context.Background(), &auth.AuthResult{
+		ProviderID:  "google-123",
+		Email:       "ana@example.com",
+		AccessToken: "new-access",
+	}, ClientInfo{}); err != nil {
+		t.Fatalf("LoginWithGoogleUser: %v", err)
+	}
+	stored, _ = repo.GetByID(user.ID.String())
+	if stored.EncryptedGoogleAccessToken != "new-access" || stored.EncryptedGoogleRefreshToken != "stored-refresh" {
+		t.Fatalf("new access token not stored: %+v", stored)
+	}
+}
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saulo-duarte/chronos-lambda/internal/auth"
+)
+
+// lastSeenInterval evita uma escrita por requisição só para atualizar o último acesso.
+const lastSeenInterval = 5 * time.Minute
+
+// Session é um login. Cada refresh troca o token e guarda só o hash do último emitido, então
+// a sessão é a família inteira de tokens: apresentar um token antigo revoga a sessão.
+type Session struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	RefreshTokenHash string     `gorm:"not null;uniqueIndex" json:"-"`
+	UserAgent        string     `json:"user_agent"`
+	IP               string     `gorm:"column:ip" json:"ip"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastSeenAt       time.Time  `json:"last_seen_at"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason    string     `json:"-"`
+}
+
+// ClientInfo identifica o dispositivo que abriu ou renovou a sessão.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func (s *Session) ToResponse(currentID string) *SessionResponse {
+	return &SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.ID.String() == currentID,
+	}
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// refreshExpiry renova o prazo do refresh token sem passar da idade máxima da sessão.
+func (s *Session) refreshExpiry(now time.Time, ttl, maxAge time.Duration) time.Time {
+	expiresAt := now.Add(ttl)
+	if limit := s.CreatedAt.Add(maxAge); expiresAt.After(limit) {
+		return limit
+	}
+	return expiresAt
+}
+
+type sessionValidator struct {
+	repo UserRepository
+}
+
+// NewSessionValidator é o que o auth.AuthMiddleware usa para recusar access tokens de sessões
+// encerradas. Também mantém o último acesso da sessão em dia.
+func NewSessionValidator(repo UserRepository) auth.SessionValidator {
+	return &sessionValidator{repo: repo}
+}
+
+func (v *sessionValidator) ActiveSession(sessionID, userID string) (bool, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return false, nil
+	}
+	session, err := v.repo.GetSession(sessionID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if session == nil || !auth.IsOwner(userID, session.UserID) || !session.IsActive(now) {
+		return false, nil
+	}
+	if now.Sub(session.LastSeenAt) > lastSeenInterval {
+		if err := v.repo.TouchSession(sessionID, now); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
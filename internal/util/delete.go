@@ -0,0 +1,30 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+type DeleteStrategy string
+
+const (
+	DeleteCascade  DeleteStrategy = "cascade"
+	DeleteReassign DeleteStrategy = "reassign"
+	DeleteDetach   DeleteStrategy = "detach"
+	DeleteBlock    DeleteStrategy = "block"
+)
+
+var ErrInvalidDeleteStrategy = errors.New("invalid delete strategy")
+
+// ParseDeleteStrategy converte o parâmetro da requisição; sem valor, a exclusão é bloqueada se houver filhos.
+func ParseDeleteStrategy(s string) (DeleteStrategy, error) {
+	if s == "" {
+		return DeleteBlock, nil
+	}
+	switch strategy := DeleteStrategy(s); strategy {
+	case DeleteCascade, DeleteReassign, DeleteDetach, DeleteBlock:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidDeleteStrategy, s)
+	}
+}
@@ -0,0 +1,64 @@
+package util
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	MinRecallGrade    = 0
+	MaxRecallGrade    = 5
+	PassingGrade      = 3
+	DefaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+var ErrInvalidRecallGrade = errors.New("recall grade must be between 0 and 5")
+
+// SRSState é o estado de repetição espaçada (SM-2) de um item revisável.
+type SRSState struct {
+	EaseFactor   float64 `json:"ease_factor"`
+	IntervalDays int     `json:"interval_days"`
+	Repetitions  int     `json:"repetitions"`
+}
+
+func NewSRSState() SRSState {
+	return SRSState{EaseFactor: DefaultEaseFactor}
+}
+
+// Review aplica uma nota de recordação (0-5) e devolve o novo estado.
+// Notas abaixo de PassingGrade reiniciam as repetições e trazem a revisão para o dia seguinte.
+func (s SRSState) Review(grade int) (SRSState, error) {
+	if grade < MinRecallGrade || grade > MaxRecallGrade {
+		return s, ErrInvalidRecallGrade
+	}
+	if s.EaseFactor < minEaseFactor {
+		s.EaseFactor = DefaultEaseFactor
+	}
+
+	if grade < PassingGrade {
+		s.Repetitions = 0
+		s.IntervalDays = 1
+	} else {
+		s.Repetitions++
+		switch s.Repetitions {
+		case 1:
+			s.IntervalDays = 1
+		case 2:
+			s.IntervalDays = 6
+		default:
+			s.IntervalDays = int(math.Round(float64(s.IntervalDays) * s.EaseFactor))
+		}
+	}
+
+	miss := float64(MaxRecallGrade - grade)
+	s.EaseFactor = math.Max(minEaseFactor, s.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	return s, nil
+}
+
+// NextReviewDate devolve o dia (à meia-noite) da próxima revisão a partir de reviewedAt.
+func (s SRSState) NextReviewDate(reviewedAt time.Time) time.Time {
+	day := time.Date(reviewedAt.Year(), reviewedAt.Month(), reviewedAt.Day(), 0, 0, 0, 0, reviewedAt.Location())
+	return day.AddDate(0, 0, s.IntervalDays)
+}
@@ -79,3 +79,31 @@ func (ldt *LocalDateTime) Scan(value interface{}) error {
 		return fmt.Errorf("cannot scan type %T into LocalDateTime", value)
 	}
 }
+
+// Fuso ----------------------
+
+// TimeZone é o fuso em que as LocalDateTime são interpretadas, o mesmo usado no Google Agenda.
+const TimeZone = "America/Sao_Paulo"
+
+var location = loadLocation()
+
+func loadLocation() *time.Location {
+	loc, err := time.LoadLocation(TimeZone)
+	if err != nil {
+		// sem tzdata no ambiente; o Brasil não tem horário de verão desde 2019
+		return time.FixedZone("-03", -3*60*60)
+	}
+	return loc
+}
+
+func Location() *time.Location {
+	return location
+}
+
+// LocalNow devolve o relógio de parede em TimeZone no mesmo formato das LocalDateTime: horário
+// local marcado como UTC. No Lambda time.Now() é UTC, então comparar direto com as datas das
+// tasks erraria em três horas.
+func LocalNow() time.Time {
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), time.UTC)
+}
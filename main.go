@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"os"
@@ -12,11 +13,19 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"github.com/saulo-duarte/chronos-lambda/internal/container"
+	"github.com/saulo-duarte/chronos-lambda/internal/project"
 	"github.com/saulo-duarte/chronos-lambda/internal/router"
 )
 
 var chiLambda *chiadapter.ChiLambdaV2
 var chiRouter *chi.Mux
+var projectService project.ProjectService
+
+// scheduledEvent identifica eventos do EventBridge, que disparam os jobs agendados.
+type scheduledEvent struct {
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+}
 
 func init() {
 	c := container.New()
@@ -24,17 +33,33 @@ func init() {
 	r := router.New(router.RouterConfig{
 		UserHandler:         c.UserContainer.Handler,
 		ProjectHandler:      c.ProjectContainer.Handler,
+		MilestoneHandler:    c.MilestoneContainer.Handler,
 		TaskHandler:         c.TaskContainer.Handler,
 		StudySubjectHandler: c.StudySubjectContainer.Handler,
 		StudyTopicHandler:   c.StudyTopicContainer.Handler,
+		FlashcardHandler:    c.FlashcardContainer.Handler,
+		AssessmentHandler:   c.AssessmentContainer.Handler,
+		StudySessionHandler: c.StudySessionContainer.Handler,
 	})
 
 	chiRouter = r.(*chi.Mux)
+	projectService = c.ProjectContainer.Service
 
 	chiLambda = chiadapter.NewV2(chiRouter)
 }
 
-func Handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+func Handler(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var event scheduledEvent
+	if err := json.Unmarshal(payload, &event); err == nil && event.Source == "aws.events" {
+		return nil, runScheduledJobs(ctx)
+	}
+
+	var req events.APIGatewayV2HTTPRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		log.Printf("ERROR: failed to decode API Gateway request: %v\n", err)
+		return nil, err
+	}
+
 	resp, err := chiLambda.ProxyWithContextV2(ctx, req)
 	if err != nil {
 		log.Printf("ERROR: ProxyWithContextV2 returned an error: %v\n", err)
@@ -42,6 +67,16 @@ func Handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.AP
 	return resp, err
 }
 
+func runScheduledJobs(ctx context.Context) error {
+	count, err := projectService.CaptureDailySnapshots(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to capture project snapshots: %v\n", err)
+		return err
+	}
+	log.Printf("Snapshots capturados para %d projetos\n", count)
+	return nil
+}
+
 func main() {
 	runMode := os.Getenv("RUN_MODE")
 
@@ -0,0 +1,18 @@
+resource "aws_cloudwatch_event_rule" "daily_snapshots" {
+  name                = "${var.lambda_function_name}-daily-snapshots"
+  description         = "Captures daily project snapshots for burndown charts"
+  schedule_expression = var.snapshot_schedule
+}
+
+resource "aws_cloudwatch_event_target" "daily_snapshots" {
+  rule = aws_cloudwatch_event_rule.daily_snapshots.name
+  arn  = aws_lambda_function.go_lambda.arn
+}
+
+resource "aws_lambda_permission" "allow_eventbridge" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.go_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.daily_snapshots.arn
+}
@@ -31,3 +31,9 @@ variable "terraform_backend_bucket" {
   description = "S3 bucket for Terraform state backend"
   default     = ""
 }
+
+variable "snapshot_schedule" {
+  type        = string
+  description = "EventBridge schedule for the daily project snapshot job (UTC); 02:55 UTC is 23:55 in America/Sao_Paulo, and the snapshot is dated with that local day"
+  default     = "cron(55 2 * * ? *)"
+}